@@ -4,7 +4,12 @@
 
 package console
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
 
 type Direction int
 
@@ -21,19 +26,55 @@ var cursorDown = string([]byte{27, 91, 'B'})
 var cursorRight = string([]byte{27, 91, 'C'})
 var cursorLeft = string([]byte{27, 91, 'D'})
 
-func hideCursor() {
-	fmt.Print("\033[?25l")
+const (
+	enterAltScreen = "\033[?1049h\033[H\033[2J"
+	leaveAltScreen = "\033[?1049l"
+)
+
+// alternateScreen is the alternate screen buffer of a terminal being used,
+// which is left when closed, restoring what was shown before.
+type alternateScreen struct {
+	w    io.Writer
+	once sync.Once
 }
 
-func showCursor() {
-	fmt.Print("\033[?25h")
+func newAlternateScreen(w io.Writer) *alternateScreen {
+
+	as := &alternateScreen{w: w}
+	fmt.Fprint(w, enterAltScreen)
+	guard.add(as)
+
+	return as
+}
+
+func (as *alternateScreen) close() {
+
+	as.once.Do(func() {
+		fmt.Fprint(as.w, leaveAltScreen)
+		guard.remove(as)
+	})
+}
+
+func hideCursor(w io.Writer) {
+	fmt.Fprint(w, "\033[?25l")
+}
+
+func showCursor(w io.Writer) {
+	fmt.Fprint(w, "\033[?25h")
 }
 
 // ClearLines clears the specified number of lines in the console.
 func ClearLines(n int) {
 
-	for i := 0; i < n-1; i++ {
-		// move cursor 1 up, move to beginning of line, erase the whole line
-		fmt.Print("\u001B[1A\u001B[1G\u001B[2K")
+	clearLines(os.Stdout, n)
+}
+
+func clearLines(w io.Writer, n int) {
+
+	if n < 2 {
+		return
 	}
+
+	fr := &frame{w: w, row: n - 1}
+	fr.clear()
 }
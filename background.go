@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Background is whether the background of the terminal is dark or light.
+type Background int
+
+const (
+	BackgroundUnknown Background = iota
+	BackgroundDark
+	BackgroundLight
+)
+
+// backgroundTimeout is how long to wait for the terminal to report its
+// background color.
+const backgroundTimeout = 200 * time.Millisecond
+
+var backgrounds = struct {
+	sync.Mutex
+	override Background
+	// detected background of terminals using a file, by file descriptor
+	files map[uintptr]Background
+}{files: map[uintptr]Background{}}
+
+// SetBackground sets the background of the terminal instead of detecting it,
+// which decides whether the light or dark variant of a theme is used. Use
+// BackgroundUnknown to detect it again.
+func SetBackground(b Background) {
+
+	backgrounds.Lock()
+	defer backgrounds.Unlock()
+
+	backgrounds.override = b
+}
+
+// background returns the background of the terminal, asking the terminal for
+// its background color when it was not set using SetBackground. The answer
+// is remembered for terminals using a file.
+func (sn *session) background() Background {
+
+	backgrounds.Lock()
+	defer backgrounds.Unlock()
+
+	if backgrounds.override != BackgroundUnknown {
+		return backgrounds.override
+	}
+
+	ft, isFile := sn.term.(*fileTerminal)
+	if isFile {
+		if b, ok := backgrounds.files[ft.out.Fd()]; ok {
+			return b
+		}
+	}
+
+	b := sn.queryBackground()
+	if b == BackgroundUnknown {
+		b = colorFgBgBackground(os.Getenv("COLORFGBG"))
+	}
+
+	if isFile {
+		backgrounds.files[ft.out.Fd()] = b
+	}
+
+	return b
+}
+
+// queryBackground asks the terminal for its background color. Keys pressed
+// while waiting for the answer are reported later by readKey.
+func (sn *session) queryBackground() Background {
+
+	waiter, ok := sn.term.(InputWaiter)
+	if !ok {
+		return BackgroundUnknown
+	}
+
+	// nearly all terminals answer the device attributes query, so there is
+	// no need to wait for the timeout when they do not support the first
+	fmt.Fprint(sn.term, "\033]11;?\033\\\033[c")
+
+	background := BackgroundUnknown
+	deadline := time.Now().Add(backgroundTimeout)
+
+	for {
+		if !sn.keys.Buffered() {
+			// a negative timeout would wait forever for some terminals
+			wait := time.Until(deadline)
+			if wait <= 0 {
+				return background
+			}
+			more, err := waiter.WaitInput(wait)
+			if err != nil || !more {
+				return background
+			}
+		}
+
+		key, err := sn.keys.ReadKey()
+		if err != nil {
+			return background
+		}
+
+		switch key.Code {
+		case keyDeviceAttributes:
+			return background
+		case keyOSC:
+			if r, g, b, ok := parseColorReport(key.report, "11;"); ok {
+				background = classifyBackground(r, g, b)
+			}
+		default:
+			sn.pending = append(sn.pending, key)
+			sn.pendingInput = append(sn.pendingInput, sn.keys.last...)
+		}
+	}
+}
+
+// parseColorReport parses the report of a color such as 11;rgb:ffff/ffff/ffff,
+// in which each component has 1 to 4 hexadecimal digits.
+func parseColorReport(report, prefix string) (r, g, b uint8, ok bool) {
+
+	spec, found := strings.CutPrefix(report, prefix)
+	if !found {
+		return 0, 0, 0, false
+	}
+
+	spec, found = strings.CutPrefix(spec, "rgb:")
+	if !found {
+		if spec, found = strings.CutPrefix(spec, "rgba:"); !found {
+			return 0, 0, 0, false
+		}
+	}
+
+	parts := strings.Split(spec, "/")
+	if len(parts) < 3 {
+		return 0, 0, 0, false
+	}
+
+	var rgb [3]uint8
+	for i := range rgb {
+		if len(parts[i]) < 1 || len(parts[i]) > 4 {
+			return 0, 0, 0, false
+		}
+		v, err := strconv.ParseUint(parts[i], 16, 16)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+		// scale to 8 bits
+		maxValue := uint64(1)<<(4*len(parts[i])) - 1
+		rgb[i] = uint8(v * 255 / maxValue)
+	}
+
+	return rgb[0], rgb[1], rgb[2], true
+}
+
+// classifyBackground returns whether a background with the given color is light
+// or dark, using its relative luminance.
+func classifyBackground(r, g, b uint8) Background {
+
+	luminance := 0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b)
+	if luminance > 127.5 {
+		return BackgroundLight
+	}
+
+	return BackgroundDark
+}
+
+// colorFgBgBackground returns the background described by the COLORFGBG
+// environment variable, which some terminals set to for example "15;0",
+// the last being the palette index of the background color.
+func colorFgBgBackground(value string) Background {
+
+	if value == "" {
+		return BackgroundUnknown
+	}
+
+	fields := strings.Split(value, ";")
+	bg, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || bg < 0 || bg > 15 {
+		return BackgroundUnknown
+	}
+
+	if bg == 7 || bg > 8 {
+		return BackgroundLight
+	}
+
+	return BackgroundDark
+}
+
+// themeVariant returns the light or dark variant of theme t matching the
+// background of the terminal, or t itself when it has none.
+func (sn *session) themeVariant(t Theme) Theme {
+
+	spec, ok := themeSpec(t)
+	if !ok || (spec.Light == "" && spec.Dark == "") {
+		return t
+	}
+
+	switch sn.background() {
+	case BackgroundLight:
+		if spec.Light != "" {
+			return spec.Light
+		}
+	case BackgroundDark:
+		if spec.Dark != "" {
+			return spec.Dark
+		}
+	}
+
+	return t
+}
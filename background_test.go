@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseColorReport(t *testing.T) {
+
+	cases := []struct {
+		report  string
+		r, g, b uint8
+		ok      bool
+	}{
+		{report: "11;rgb:ffff/ffff/ffff", r: 255, g: 255, b: 255, ok: true},
+		{report: "11;rgb:0000/0000/0000", ok: true},
+		{report: "11;rgb:1e1e/2020/3030", r: 0x1e, g: 0x20, b: 0x30, ok: true},
+		{report: "11;rgb:f/8/0", r: 255, g: 136, b: 0, ok: true},
+		{report: "11;rgb:ff/80/00", r: 255, g: 128, b: 0, ok: true},
+		{report: "11;rgb:fff/000/fff", r: 255, g: 0, b: 255, ok: true},
+		{report: "11;rgba:ffff/0000/0000/ffff", r: 255, ok: true},
+		{report: "10;rgb:ffff/ffff/ffff"},
+		{report: "11;#ffffff"},
+		{report: "11;rgb:ffff/ffff"},
+		{report: "11;rgb:fffff/0/0"},
+		{report: "11;rgb:/0/0"},
+		{report: "11;rgb:zz/0/0"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.report, func(t *testing.T) {
+			r, g, b, ok := parseColorReport(c.report, "11;")
+			if ok != c.ok {
+				t.Fatalf("got ok %v; want %v", ok, c.ok)
+			}
+			if r != c.r || g != c.g || b != c.b {
+				t.Errorf("got %d,%d,%d; want %d,%d,%d", r, g, b, c.r, c.g, c.b)
+			}
+		})
+	}
+}
+
+func TestClassifyBackground(t *testing.T) {
+
+	cases := []struct {
+		name    string
+		r, g, b uint8
+		want    Background
+	}{
+		{name: "black", want: BackgroundDark},
+		{name: "white", r: 255, g: 255, b: 255, want: BackgroundLight},
+		{name: "solarized dark", r: 0x00, g: 0x2b, b: 0x36, want: BackgroundDark},
+		{name: "solarized light", r: 0xfd, g: 0xf6, b: 0xe3, want: BackgroundLight},
+		{name: "pure blue", b: 255, want: BackgroundDark},
+		{name: "pure green", g: 255, want: BackgroundLight},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := classifyBackground(c.r, c.g, c.b); got != c.want {
+				t.Errorf("got %v; want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestColorFgBgBackground(t *testing.T) {
+
+	cases := []struct {
+		value string
+		want  Background
+	}{
+		{value: "", want: BackgroundUnknown},
+		{value: "15;0", want: BackgroundDark},
+		{value: "0;15", want: BackgroundLight},
+		{value: "0;7", want: BackgroundLight},
+		{value: "7;8", want: BackgroundDark},
+		{value: "15;default;0", want: BackgroundDark},
+		{value: "0;default", want: BackgroundUnknown},
+		{value: "0;16", want: BackgroundUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			if got := colorFgBgBackground(c.value); got != c.want {
+				t.Errorf("got %v; want %v", got, c.want)
+			}
+		})
+	}
+}
+
+// slowTerminal reports input only after the time to wait for the background
+// color has passed, recording the timeouts it is asked to wait.
+type slowTerminal struct {
+	*strings.Reader
+	strings.Builder
+
+	timeouts []time.Duration
+}
+
+func (st *slowTerminal) Size() (int, int, error)        { return 80, 24, nil }
+func (st *slowTerminal) IsTerminal() bool               { return true }
+func (st *slowTerminal) MakeRaw() (func() error, error) { return func() error { return nil }, nil }
+
+func (st *slowTerminal) WaitInput(timeout time.Duration) (bool, error) {
+
+	st.timeouts = append(st.timeouts, timeout)
+	if len(st.timeouts) == 1 {
+		time.Sleep(timeout + 10*time.Millisecond)
+	}
+
+	return st.Reader.Len() > 0, nil
+}
+
+func TestSession_queryBackground_deadline(t *testing.T) {
+
+	st := &slowTerminal{Reader: strings.NewReader("x")}
+	sn := &session{term: st, keys: NewKeyReader(st)}
+
+	if got := sn.queryBackground(); got != BackgroundUnknown {
+		t.Errorf("got %v; want unknown background", got)
+	}
+
+	for _, timeout := range st.timeouts {
+		if timeout <= 0 {
+			t.Errorf("waited for input with timeout %s after the deadline", timeout)
+		}
+	}
+
+	if len(sn.pending) != 1 || sn.pending[0].Rune != 'x' {
+		t.Errorf("got pending keys %+v; want the key pressed while waiting", sn.pending)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ColorDepth is the number of colors a terminal can show.
+type ColorDepth int
+
+const (
+	ColorNone ColorDepth = iota
+	Color16
+	Color256
+	ColorTrue
+)
+
+// Capabilities describes what a terminal supports.
+type Capabilities struct {
+	// TTY is whether output goes to a terminal.
+	TTY bool
+	// Dumb is whether the terminal does not support escape sequences, like
+	// when TERM is set to "dumb".
+	Dumb bool
+	// Color is the number of colors which can be used. It is ColorNone when
+	// NO_COLOR is set.
+	Color ColorDepth
+	// Unicode is whether characters outside ASCII can be shown, which is
+	// derived from the locale.
+	Unicode bool
+}
+
+// CapabilitiesReporter is implemented by a Terminal which knows its
+// capabilities, for example from what the client of an SSH session sent.
+type CapabilitiesReporter interface {
+	Capabilities() Capabilities
+}
+
+// DetectCapabilities returns the capabilities of t based on whether its output
+// is a terminal, and the environment variables TERM, COLORTERM, NO_COLOR,
+// FORCE_COLOR, and the locale. When t implements CapabilitiesReporter, what it
+// reports is returned instead.
+func DetectCapabilities(t Terminal) Capabilities {
+
+	if cr, ok := t.(CapabilitiesReporter); ok {
+		return cr.Capabilities()
+	}
+
+	return detectCapabilities(isOutputTerminal(t), os.Getenv)
+}
+
+func isOutputTerminal(t Terminal) bool {
+
+	if ft, ok := t.(*fileTerminal); ok {
+		return term.IsTerminal(int(ft.out.Fd()))
+	}
+
+	return t.IsTerminal()
+}
+
+func detectCapabilities(tty bool, getenv func(string) string) Capabilities {
+
+	termName := strings.ToLower(getenv("TERM"))
+
+	caps := Capabilities{
+		TTY:     tty,
+		Dumb:    termName == "dumb",
+		Unicode: termName != "dumb" && unicodeLocale(getenv),
+	}
+
+	caps.Color = colorDepth(caps, termName, getenv)
+
+	return caps
+}
+
+func colorDepth(caps Capabilities, termName string, getenv func(string) string) ColorDepth {
+
+	if getenv("NO_COLOR") != "" {
+		return ColorNone
+	}
+
+	if force := getenv("FORCE_COLOR"); force != "" {
+		switch strings.ToLower(force) {
+		case "0", "false":
+			return ColorNone
+		case "2":
+			return Color256
+		case "3":
+			return ColorTrue
+		default:
+			return Color16
+		}
+	}
+
+	if !caps.TTY || caps.Dumb {
+		return ColorNone
+	}
+
+	switch colorTerm := strings.ToLower(getenv("COLORTERM")); {
+	case colorTerm == "truecolor" || colorTerm == "24bit":
+		return ColorTrue
+	case strings.Contains(termName, "truecolor") || strings.Contains(termName, "direct"):
+		return ColorTrue
+	case strings.Contains(termName, "256color"):
+		return Color256
+	case termName == "" && runtime.GOOS == "windows" && getenv("WT_SESSION") != "":
+		return ColorTrue
+	}
+
+	return Color16
+}
+
+// unicodeLocale returns whether the locale uses UTF-8, looking at LC_ALL,
+// LC_CTYPE and LANG in that order.
+func unicodeLocale(getenv func(string) string) bool {
+
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := strings.ToLower(getenv(name)); v != "" {
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+
+	// Windows Terminal handles Unicode without a locale being set
+	return runtime.GOOS == "windows" && getenv("WT_SESSION") != ""
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import "testing"
+
+func TestDetectCapabilities(t *testing.T) {
+
+	cases := []struct {
+		name string
+		tty  bool
+		env  map[string]string
+		want Capabilities
+	}{
+		{
+			name: "terminal",
+			tty:  true,
+			env:  map[string]string{"TERM": "xterm", "LANG": "en_US.UTF-8"},
+			want: Capabilities{TTY: true, Color: Color16, Unicode: true},
+		},
+		{
+			name: "256 colors",
+			tty:  true,
+			env:  map[string]string{"TERM": "xterm-256color"},
+			want: Capabilities{TTY: true, Color: Color256},
+		},
+		{
+			name: "COLORTERM truecolor",
+			tty:  true,
+			env:  map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"},
+			want: Capabilities{TTY: true, Color: ColorTrue},
+		},
+		{
+			name: "COLORTERM 24bit",
+			tty:  true,
+			env:  map[string]string{"TERM": "xterm", "COLORTERM": "24bit"},
+			want: Capabilities{TTY: true, Color: ColorTrue},
+		},
+		{
+			name: "NO_COLOR",
+			tty:  true,
+			env:  map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"},
+			want: Capabilities{TTY: true, Color: ColorNone},
+		},
+		{
+			name: "NO_COLOR wins over FORCE_COLOR",
+			tty:  true,
+			env:  map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "3"},
+			want: Capabilities{TTY: true, Color: ColorNone},
+		},
+		{
+			name: "FORCE_COLOR without terminal",
+			env:  map[string]string{"FORCE_COLOR": "1"},
+			want: Capabilities{Color: Color16},
+		},
+		{
+			name: "FORCE_COLOR 3",
+			env:  map[string]string{"FORCE_COLOR": "3"},
+			want: Capabilities{Color: ColorTrue},
+		},
+		{
+			name: "FORCE_COLOR 0",
+			tty:  true,
+			env:  map[string]string{"TERM": "xterm", "FORCE_COLOR": "0"},
+			want: Capabilities{TTY: true, Color: ColorNone},
+		},
+		{
+			name: "dumb",
+			tty:  true,
+			env:  map[string]string{"TERM": "dumb", "LANG": "en_US.UTF-8"},
+			want: Capabilities{TTY: true, Dumb: true, Color: ColorNone},
+		},
+		{
+			name: "no terminal",
+			env:  map[string]string{"TERM": "xterm-256color", "LANG": "en_US.UTF-8"},
+			want: Capabilities{Color: ColorNone, Unicode: true},
+		},
+		{
+			name: "C locale",
+			tty:  true,
+			env:  map[string]string{"TERM": "xterm", "LANG": "C"},
+			want: Capabilities{TTY: true, Color: Color16},
+		},
+		{
+			name: "LC_ALL wins over LANG",
+			tty:  true,
+			env:  map[string]string{"TERM": "xterm", "LC_ALL": "C", "LANG": "en_US.UTF-8"},
+			want: Capabilities{TTY: true, Color: Color16},
+		},
+		{
+			name: "LC_CTYPE utf8",
+			tty:  true,
+			env:  map[string]string{"TERM": "xterm", "LC_CTYPE": "nl_BE.utf8"},
+			want: Capabilities{TTY: true, Color: Color16, Unicode: true},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := detectCapabilities(c.tty, func(name string) string { return c.env[name] })
+			if got != c.want {
+				t.Errorf("got %+v; want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestFallbackTheme(t *testing.T) {
+
+	full := Capabilities{TTY: true, Color: ColorTrue, Unicode: true}
+
+	cases := []struct {
+		name  string
+		theme Theme
+		caps  Capabilities
+		want  Theme
+	}{
+		{name: "nerdfont supported", theme: ThemeNerdFont, caps: full, want: ThemeNerdFont},
+		{name: "nerdfont without UTF-8", theme: ThemeNerdFont, caps: Capabilities{TTY: true, Color: Color16}, want: ThemeAscii},
+		{name: "color01 supported", theme: ThemeColor01, caps: full, want: ThemeColor01},
+		{name: "color01 under NO_COLOR", theme: ThemeColor01, caps: Capabilities{TTY: true, Unicode: true}, want: ThemeInverted},
+		{name: "color01 on dumb terminal", theme: ThemeColor01, caps: Capabilities{TTY: true, Dumb: true}, want: ThemeAscii},
+		{name: "inverted on dumb terminal", theme: ThemeInverted, caps: Capabilities{TTY: true, Dumb: true}, want: ThemeAscii},
+		{name: "ascii", theme: ThemeAscii, caps: Capabilities{}, want: ThemeAscii},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := fallbackTheme(c.theme, c.caps); got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestFallbackTheme_cycle(t *testing.T) {
+
+	spec := ThemeSpec{
+		Selection: SelectionTheme{Unselected: "  %s", Selected: "→ %s"},
+		Toggle:    ToggleTheme{Unselected: "%s", Selected: "→ %s"},
+		Requires:  ThemeRequirements{Unicode: true},
+	}
+
+	first, second := Theme("test-cycle-first"), Theme("test-cycle-second")
+	if err := RegisterTheme(first, spec); err != nil {
+		t.Fatal(err)
+	}
+	spec.Fallback = first
+	if err := RegisterTheme(second, spec); err != nil {
+		t.Fatal(err)
+	}
+	spec.Fallback = second
+	if err := RegisterTheme(first, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fallbackTheme(first, Capabilities{TTY: true}); got != ThemeAscii {
+		t.Errorf("got %q; want %q", got, ThemeAscii)
+	}
+}
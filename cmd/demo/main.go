@@ -11,6 +11,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/golistic/console"
 )
@@ -18,17 +19,33 @@ import (
 func main() {
 	var themeArg string
 
-	flag.StringVar(&themeArg, "theme", "ascii", "Theme to use")
+	flag.StringVar(&themeArg, "theme", "", "Theme to use, or JSON file defining one (default $"+console.EnvTheme+" or ascii)")
 	flag.Parse()
 
 	if len(flag.Args()) == 0 {
-		fmt.Println("Usage: demo [-theme=<theme>] [toggle|selection]")
+		fmt.Println("Usage: demo [-theme=<theme>] [toggle|selection|multi]")
+	}
+
+	theme, err := console.ThemeFromEnv()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if strings.HasSuffix(themeArg, ".json") {
+		if themeArg, err = loadTheme(themeArg); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 	}
 
-	theme := console.ThemeAscii
 	if themeArg != "" {
 		if t, ok := console.ThemeLookup(themeArg); !ok {
-			fmt.Println("Theme must be one of: ascii, nerdfont, color01, inverted")
+			var names []string
+			for _, t := range console.Themes() {
+				names = append(names, string(t))
+			}
+			fmt.Println("Theme must be one of:", strings.Join(names, ", "))
 			os.Exit(1)
 		} else {
 			theme = t
@@ -42,6 +59,8 @@ func main() {
 			err = toggle(theme)
 		case "selection":
 			err = selection(theme)
+		case "multi":
+			err = multiSelection(theme)
 		case "form":
 			err = useForm(theme)
 		}
@@ -55,6 +74,12 @@ func main() {
 	}
 }
 
+func loadTheme(path string) (string, error) {
+
+	t, err := console.LoadTheme(path)
+	return string(t), err
+}
+
 func toggle(theme console.Theme) error {
 
 	options := []string{"Absolutely!", "No.."}
@@ -102,6 +127,26 @@ func selection(theme console.Theme) error {
 	return nil
 }
 
+func multiSelection(theme console.Theme) error {
+
+	options := []string{"Go", "Python", "Rust", "Zig", "C", "Java"}
+
+	s, err := console.NewMultiSelection(options, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.SetChecked(0)
+	s.SetMin(1)
+	s.SetMax(3)
+
+	if err := s.RenderWithTheme(theme); err != nil {
+		return err
+	}
+
+	fmt.Println("Selected:", s.Selected())
+	return nil
+}
+
 func useForm(theme console.Theme) error {
 
 	programmingLanugages := console.SelectProps{
@@ -0,0 +1,23 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+// Package consoletest provides a virtual terminal for testing the widgets of
+// package console without a real terminal.
+//
+// A Terminal is given a script of keys to type, and interprets what the widget
+// writes on a Screen, which can be compared with golden files:
+//
+//	term := consoletest.NewTerminal(40, 10)
+//	term.Type(consoletest.Down, consoletest.Down)
+//	term.Do(func() {
+//		consoletest.Golden(t, "selection_down", term.Screen().String())
+//	})
+//	term.Type(consoletest.Enter)
+//
+//	s, _ := console.NewSelection(options, values)
+//	s.SetTerminal(term)
+//	if err := s.Render(); err != nil {
+//		t.Fatal(err)
+//	}
+package consoletest
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package consoletest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable which, when set, makes Golden
+// write the golden files instead of comparing with them.
+const UpdateGoldenEnv = "CONSOLETEST_UPDATE"
+
+// Golden compares got with the contents of the file testdata/<name>.golden,
+// failing t when they differ. When the environment variable UpdateGoldenEnv is
+// set, the file is written with got instead.
+func Golden(t testing.TB, name string, got string) {
+
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (%s); set %s=1 to create it", err, UpdateGoldenEnv)
+	}
+
+	if got != string(want) {
+		t.Errorf("screen does not match %s\n--- got:\n%s\n--- want:\n%s", path, got, want)
+	}
+}
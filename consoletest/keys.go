@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package consoletest
+
+import "fmt"
+
+// Keys as sent by a terminal, to be used with Terminal.Type.
+const (
+	Enter     = "\r"
+	Tab       = "\t"
+	ShiftTab  = "\x1b[Z"
+	Backspace = "\x7f"
+	Escape    = "\x1b"
+	Space     = " "
+	Up        = "\x1b[A"
+	Down      = "\x1b[B"
+	Right     = "\x1b[C"
+	Left      = "\x1b[D"
+	Home      = "\x1b[H"
+	End       = "\x1b[F"
+	PageUp    = "\x1b[5~"
+	PageDown  = "\x1b[6~"
+	Insert    = "\x1b[2~"
+	Delete    = "\x1b[3~"
+	CtrlC     = "\x03"
+	CtrlZ     = "\x1a"
+)
+
+// Ctrl returns the key sent when holding Ctrl and pressing the letter r.
+func Ctrl(r rune) string {
+
+	return string([]byte{byte(r|0x20) - 'a' + 1})
+}
+
+// Alt returns the key sent when holding Alt and pressing key.
+func Alt(key string) string {
+
+	return "\x1b" + key
+}
+
+// Click returns the mouse reports sent when clicking the left button at the
+// given column and row, both starting at 1. Clicking twice in a row is a
+// double-click.
+func Click(col, row int) string {
+
+	return mouse(0, col, row, 'M') + mouse(0, col, row, 'm')
+}
+
+// WheelUp returns the mouse report sent when turning the wheel up while
+// pointing at the given column and row, both starting at 1.
+func WheelUp(col, row int) string {
+
+	return mouse(64, col, row, 'M')
+}
+
+// WheelDown returns the mouse report sent when turning the wheel down while
+// pointing at the given column and row, both starting at 1.
+func WheelDown(col, row int) string {
+
+	return mouse(65, col, row, 'M')
+}
+
+func mouse(button, col, row int, final byte) string {
+
+	return fmt.Sprintf("\x1b[<%d;%d;%d%c", button, col, row, final)
+}
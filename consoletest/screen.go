@@ -0,0 +1,638 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package consoletest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// Cell is a single position on the Screen.
+type Cell struct {
+	// Text is what is shown in the cell; it is empty for blank cells and for the
+	// second cell of a wide character.
+	Text string
+	// Style is the SGR parameters the text was written with, for example "1;32".
+	Style string
+
+	// wide is set on the second cell of a wide character
+	wide bool
+}
+
+// Screen is a virtual terminal screen. It interprets the text and the control
+// sequences written to it, keeping a grid of cells and the cursor position.
+type Screen struct {
+	mu sync.Mutex
+
+	width  int
+	height int
+
+	main      [][]Cell
+	alternate [][]Cell
+	cells     [][]Cell
+
+	row         int
+	col         int
+	pendingWrap bool
+	// joining is set when the next character joins the previous cell, like
+	// after a zero width joiner or the first half of a flag
+	joining  bool
+	savedRow int
+	savedCol int
+
+	style         sgrState
+	cursorVisible bool
+	modes         map[int]bool
+
+	// newlineCR is set when a line feed also returns the cursor to the first
+	// column, as the terminal driver does when not in raw mode.
+	newlineCR bool
+
+	// background color reported when queried, empty when not answering
+	background string
+
+	pending []byte
+	reply   func(string)
+}
+
+// NewScreen returns a blank Screen with the given dimensions.
+func NewScreen(width, height int) *Screen {
+
+	s := &Screen{
+		width:         width,
+		height:        height,
+		cursorVisible: true,
+		newlineCR:     true,
+		modes:         map[int]bool{},
+	}
+
+	s.main = newGrid(width, height)
+	s.cells = s.main
+
+	return s
+}
+
+func newGrid(width, height int) [][]Cell {
+
+	grid := make([][]Cell, height)
+	for i := range grid {
+		grid[i] = make([]Cell, width)
+	}
+
+	return grid
+}
+
+// Size returns the dimensions of the screen.
+func (s *Screen) Size() (width, height int) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.width, s.height
+}
+
+// Cursor returns the position of the cursor; both row and column start at 0.
+func (s *Screen) Cursor() (row, col int) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.row, s.col
+}
+
+// CursorVisible returns whether the cursor is shown.
+func (s *Screen) CursorVisible() bool {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cursorVisible
+}
+
+// SetBackgroundColor sets the background color which the screen reports when
+// asked using OSC 11. By default, the query is not answered, like many
+// terminals do.
+func (s *Screen) SetBackgroundColor(r, g, b uint8) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.background = fmt.Sprintf("rgb:%02x%02x/%02x%02x/%02x%02x", r, r, g, g, b, b)
+}
+
+// Mode returns whether the DEC private mode n, for example 1049 for the
+// alternate screen, was enabled.
+func (s *Screen) Mode(n int) bool {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.modes[n]
+}
+
+// Cell returns the cell at the given row and column.
+func (s *Screen) Cell(row, col int) Cell {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if row < 0 || row >= s.height || col < 0 || col >= s.width {
+		return Cell{}
+	}
+
+	return s.cells[row][col]
+}
+
+// Line returns the text on the given row without trailing blanks.
+func (s *Screen) Line(row int) string {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if row < 0 || row >= s.height {
+		return ""
+	}
+
+	return s.line(row, false)
+}
+
+// String returns the text on the screen, one line per row, without trailing
+// blanks and without trailing empty lines.
+func (s *Screen) String() string {
+
+	return s.snapshot(false)
+}
+
+// StyledString returns the text on the screen like String, but marking where
+// the style changes using the SGR parameters in curly braces, for example
+// "{1;32}Yes{} No".
+func (s *Screen) StyledString() string {
+
+	return s.snapshot(true)
+}
+
+func (s *Screen) snapshot(styled bool) string {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines := make([]string, s.height)
+	for i := range lines {
+		lines[i] = s.line(i, styled)
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (s *Screen) line(row int, styled bool) string {
+
+	var b strings.Builder
+	var style string
+	var blanks int
+
+	for _, c := range s.cells[row] {
+		if styled && c.Style != style {
+			if blanks > 0 {
+				b.WriteString(strings.Repeat(" ", blanks))
+				blanks = 0
+			}
+			b.WriteString("{" + c.Style + "}")
+			style = c.Style
+		}
+
+		switch {
+		case c.wide:
+		case c.Text == "" || c.Text == " ":
+			blanks++
+		default:
+			if blanks > 0 {
+				b.WriteString(strings.Repeat(" ", blanks))
+				blanks = 0
+			}
+			b.WriteString(c.Text)
+		}
+	}
+
+	if styled && style != "" {
+		b.WriteString("{}")
+	}
+
+	return b.String()
+}
+
+// Write interprets p as output of a program running in the terminal.
+func (s *Screen) Write(p []byte) (int, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := append(s.pending, p...)
+	s.pending = nil
+
+	for len(data) > 0 {
+		n := s.consume(data)
+		if n == 0 {
+			// incomplete sequence or character; wait for more
+			s.pending = append([]byte{}, data...)
+			break
+		}
+		data = data[n:]
+	}
+
+	return len(p), nil
+}
+
+// consume interprets what is at the start of data and returns how many bytes
+// were used, or 0 when more are needed.
+func (s *Screen) consume(data []byte) int {
+
+	switch c := data[0]; c {
+	case 0x1b:
+		return s.escape(data)
+	case '\r':
+		s.col = 0
+		s.pendingWrap = false
+	case '\n', 0x0b, 0x0c:
+		s.lineFeed()
+		if s.newlineCR {
+			s.col = 0
+		}
+	case '\b':
+		if s.col > 0 {
+			s.col--
+		}
+		s.pendingWrap = false
+	case '\t':
+		s.col = min(s.width-1, (s.col/8+1)*8)
+	default:
+		if c < 0x20 || c == 0x7f {
+			return 1
+		}
+
+		if !utf8.FullRune(data) {
+			return 0
+		}
+
+		r, n := utf8.DecodeRune(data)
+		s.put(r)
+		return n
+	}
+
+	return 1
+}
+
+func (s *Screen) escape(data []byte) int {
+
+	if len(data) < 2 {
+		return 0
+	}
+
+	switch data[1] {
+	case '[':
+		for i := 2; i < len(data); i++ {
+			if data[i] >= 0x40 && data[i] <= 0x7e {
+				s.csi(string(data[2:i]), data[i])
+				return i + 1
+			}
+		}
+		return 0
+	case ']':
+		for i := 2; i < len(data); i++ {
+			if data[i] == 0x07 {
+				s.osc(string(data[2:i]))
+				return i + 1
+			}
+			if data[i] == 0x1b && i+1 < len(data) && data[i+1] == '\\' {
+				s.osc(string(data[2:i]))
+				return i + 2
+			}
+		}
+		return 0
+	case '7':
+		s.savedRow, s.savedCol = s.row, s.col
+	case '8':
+		s.row, s.col = s.savedRow, s.savedCol
+		s.pendingWrap = false
+	case 'M':
+		if s.row == 0 {
+			s.scrollDown(1)
+		} else {
+			s.row--
+		}
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.lineFeed()
+		s.col = 0
+	}
+
+	return 2
+}
+
+func (s *Screen) csi(params string, final byte) {
+
+	private := strings.HasPrefix(params, "?")
+	args := parseParams(strings.TrimPrefix(params, "?"))
+
+	arg := func(i, def int) int {
+		if i < len(args) && args[i] > 0 {
+			return args[i]
+		}
+		return def
+	}
+
+	if final != 'm' {
+		s.pendingWrap = false
+	}
+
+	switch final {
+	case 'A':
+		s.row = max(0, s.row-arg(0, 1))
+	case 'B':
+		s.row = min(s.height-1, s.row+arg(0, 1))
+	case 'C':
+		s.col = min(s.width-1, s.col+arg(0, 1))
+	case 'D':
+		s.col = max(0, s.col-arg(0, 1))
+	case 'E':
+		s.row = min(s.height-1, s.row+arg(0, 1))
+		s.col = 0
+	case 'F':
+		s.row = max(0, s.row-arg(0, 1))
+		s.col = 0
+	case 'G':
+		s.col = min(s.width-1, arg(0, 1)-1)
+	case 'd':
+		s.row = min(s.height-1, arg(0, 1)-1)
+	case 'H', 'f':
+		s.row = min(s.height-1, arg(0, 1)-1)
+		s.col = min(s.width-1, arg(1, 1)-1)
+	case 'J':
+		s.eraseDisplay(arg(0, 0))
+	case 'K':
+		s.eraseLine(arg(0, 0))
+	case 'X':
+		s.clear(s.row, s.col, min(s.width, s.col+arg(0, 1)))
+	case 'P':
+		row := s.cells[s.row]
+		n := min(arg(0, 1), s.width-s.col)
+		copy(row[s.col:], row[s.col+n:])
+		s.clear(s.row, s.width-n, s.width)
+	case '@':
+		row := s.cells[s.row]
+		n := min(arg(0, 1), s.width-s.col)
+		copy(row[s.col+n:], row[s.col:])
+		s.clear(s.row, s.col, s.col+n)
+	case 'S':
+		s.scrollUp(arg(0, 1))
+	case 'T':
+		s.scrollDown(arg(0, 1))
+	case 's':
+		s.savedRow, s.savedCol = s.row, s.col
+	case 'u':
+		s.row, s.col = s.savedRow, s.savedCol
+	case 'm':
+		s.style.apply(args)
+	case 'c':
+		// report to be a VT220 when asked for the device attributes
+		if !private && arg(0, 0) == 0 && s.reply != nil {
+			s.reply("\x1b[?62c")
+		}
+	case 'n':
+		if !private && arg(0, 0) == 6 && s.reply != nil {
+			s.reply("\x1b[" + strconv.Itoa(s.row+1) + ";" + strconv.Itoa(s.col+1) + "R")
+		}
+	case 'h', 'l':
+		if private {
+			for _, mode := range args {
+				s.setMode(mode, final == 'h')
+			}
+		}
+	}
+}
+
+func (s *Screen) osc(command string) {
+
+	if command == "11;?" && s.background != "" && s.reply != nil {
+		s.reply("\x1b]11;" + s.background + "\x1b\\")
+	}
+}
+
+func (s *Screen) setMode(mode int, on bool) {
+
+	s.modes[mode] = on
+
+	switch mode {
+	case 25:
+		s.cursorVisible = on
+	case 1049:
+		if on && s.alternate == nil {
+			s.savedRow, s.savedCol = s.row, s.col
+			s.alternate = newGrid(s.width, s.height)
+			s.cells = s.alternate
+		} else if !on && s.alternate != nil {
+			s.alternate = nil
+			s.cells = s.main
+			s.row, s.col = s.savedRow, s.savedCol
+		}
+	}
+}
+
+func (s *Screen) put(r rune) {
+
+	w := runeWidth(r)
+
+	joining := s.joining
+	switch {
+	case r == 0x200d:
+		s.joining = true
+	case r >= 0x1f1e6 && r <= 0x1f1ff:
+		s.joining = !joining
+	default:
+		s.joining = false
+	}
+
+	if w == 0 || joining {
+		// combining characters join the previous cell
+		col := s.col - 1
+		if s.pendingWrap {
+			col = s.col
+		}
+		for col > 0 && s.cells[s.row][col].wide {
+			col--
+		}
+		if col >= 0 {
+			s.cells[s.row][col].Text += string(r)
+		}
+		return
+	}
+
+	if s.pendingWrap || s.col+w > s.width {
+		s.lineFeed()
+		s.col = 0
+		s.pendingWrap = false
+	}
+
+	s.cells[s.row][s.col] = Cell{Text: string(r), Style: s.style.String()}
+	if w == 2 {
+		s.cells[s.row][s.col+1] = Cell{Style: s.style.String(), wide: true}
+	}
+
+	s.col += w
+	if s.col >= s.width {
+		s.col = s.width - 1
+		s.pendingWrap = true
+	}
+}
+
+func (s *Screen) lineFeed() {
+
+	s.pendingWrap = false
+
+	if s.row == s.height-1 {
+		s.scrollUp(1)
+		return
+	}
+
+	s.row++
+}
+
+func (s *Screen) scrollUp(n int) {
+
+	n = min(n, s.height)
+	copy(s.cells, s.cells[n:])
+	for i := s.height - n; i < s.height; i++ {
+		s.cells[i] = make([]Cell, s.width)
+	}
+}
+
+func (s *Screen) scrollDown(n int) {
+
+	n = min(n, s.height)
+	copy(s.cells[n:], s.cells)
+	for i := 0; i < n; i++ {
+		s.cells[i] = make([]Cell, s.width)
+	}
+}
+
+func (s *Screen) clear(row, from, to int) {
+
+	for i := max(0, from); i < min(to, s.width); i++ {
+		s.cells[row][i] = Cell{}
+	}
+}
+
+func (s *Screen) eraseLine(mode int) {
+
+	switch mode {
+	case 0:
+		s.clear(s.row, s.col, s.width)
+	case 1:
+		s.clear(s.row, 0, s.col+1)
+	case 2:
+		s.clear(s.row, 0, s.width)
+	}
+}
+
+func (s *Screen) eraseDisplay(mode int) {
+
+	switch mode {
+	case 0:
+		s.clear(s.row, s.col, s.width)
+		for i := s.row + 1; i < s.height; i++ {
+			s.clear(i, 0, s.width)
+		}
+	case 1:
+		s.clear(s.row, 0, s.col+1)
+		for i := 0; i < s.row; i++ {
+			s.clear(i, 0, s.width)
+		}
+	case 2, 3:
+		for i := 0; i < s.height; i++ {
+			s.clear(i, 0, s.width)
+		}
+	}
+}
+
+// resize changes the dimensions of the screen, keeping what fits.
+func (s *Screen) resize(width, height int) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fit := func(grid [][]Cell) [][]Cell {
+		if grid == nil {
+			return nil
+		}
+		resized := newGrid(width, height)
+		for i := 0; i < min(height, len(grid)); i++ {
+			copy(resized[i], grid[i])
+		}
+		return resized
+	}
+
+	alternate := s.alternate != nil
+	s.main = fit(s.main)
+	s.alternate = fit(s.alternate)
+	s.cells = s.main
+	if alternate {
+		s.cells = s.alternate
+	}
+
+	s.width, s.height = width, height
+	s.row = min(s.row, height-1)
+	s.col = min(s.col, width-1)
+	s.pendingWrap = false
+}
+
+func (s *Screen) setRaw(raw bool) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.newlineCR = !raw
+}
+
+func runeWidth(r rune) int {
+
+	switch {
+	case r == 0x200d || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case r >= 0x1f1e6 && r <= 0x1f1ff, r >= 0x1f300 && r <= 0x1faff:
+		return 2
+	}
+
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+
+	return 1
+}
+
+func parseParams(s string) []int {
+
+	if s == "" {
+		return nil
+	}
+
+	fields := strings.Split(s, ";")
+	params := make([]int, len(fields))
+	for i, f := range fields {
+		params[i], _ = strconv.Atoi(f)
+	}
+
+	return params
+}
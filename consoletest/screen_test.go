@@ -0,0 +1,218 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package consoletest
+
+import (
+	"testing"
+)
+
+func TestScreen_Write(t *testing.T) {
+
+	cases := []struct {
+		name   string
+		width  int
+		height int
+		input  string
+		want   string
+	}{
+		{name: "text", width: 10, height: 3, input: "hello", want: "hello"},
+		{name: "line feed returns", width: 10, height: 3, input: "ab\ncd", want: "ab\ncd"},
+		{name: "carriage return overwrites", width: 10, height: 3, input: "abc\rX", want: "Xbc"},
+		{name: "backspace", width: 10, height: 3, input: "abc\bX", want: "abX"},
+		{name: "tab stops", width: 20, height: 3, input: "a\tb", want: "a       b"},
+		{name: "wraps at last column", width: 4, height: 3, input: "abcdef", want: "abcd\nef"},
+		{name: "pending wrap cancelled by return", width: 4, height: 3, input: "abcd\rX", want: "Xbcd"},
+		{name: "scrolls at bottom", width: 5, height: 2, input: "1\n2\n3", want: "2\n3"},
+		{name: "wide characters", width: 6, height: 3, input: "日本x", want: "日本x"},
+		{name: "wide character wraps", width: 3, height: 3, input: "a日本", want: "a日\n本"},
+		{name: "combining character", width: 6, height: 3, input: "éx", want: "éx"},
+		{name: "flag joins", width: 6, height: 3, input: "\U0001F1F3\U0001F1F1x", want: "\U0001F1F3\U0001F1F1x"},
+
+		{name: "cursor up", width: 10, height: 3, input: "ab\ncd\x1b[AX", want: "abX\ncd"},
+		{name: "cursor down", width: 10, height: 3, input: "ab\x1b[2BX", want: "ab\n\n  X"},
+		{name: "cursor forward and back", width: 10, height: 3, input: "a\x1b[3CX\x1b[2DY", want: "a  YX"},
+		{name: "cursor position", width: 10, height: 3, input: "\x1b[2;4HX", want: "\n   X"},
+		{name: "cursor column", width: 10, height: 3, input: "abcdef\x1b[3GX", want: "abXdef"},
+		{name: "cursor previous line", width: 10, height: 3, input: "ab\ncd\x1b[FX", want: "Xb\ncd"},
+		{name: "erase to end of line", width: 10, height: 3, input: "abcdef\x1b[3G\x1b[K", want: "ab"},
+		{name: "erase to start of line", width: 10, height: 3, input: "abcdef\x1b[3G\x1b[1K", want: "   def"},
+		{name: "erase line", width: 10, height: 3, input: "abcdef\x1b[2K", want: ""},
+		{name: "erase below", width: 10, height: 3, input: "ab\ncd\nef\x1b[2;2H\x1b[J", want: "ab\nc"},
+		{name: "erase display", width: 10, height: 3, input: "ab\ncd\x1b[2J", want: ""},
+		{name: "erase characters", width: 10, height: 3, input: "abcdef\x1b[2G\x1b[2X", want: "a  def"},
+		{name: "delete characters", width: 10, height: 3, input: "abcdef\x1b[2G\x1b[2P", want: "adef"},
+		{name: "insert characters", width: 10, height: 3, input: "abc\x1b[2G\x1b[2@", want: "a  bc"},
+		{name: "save and restore cursor", width: 10, height: 3, input: "a\x1b7\nbc\x1b8X", want: "aX\nbc"},
+		{name: "reverse index scrolls down", width: 10, height: 3, input: "a\x1b[H\x1bMb", want: "b\na"},
+		{name: "scroll up", width: 10, height: 3, input: "a\nb\nc\x1b[1S", want: "b\nc"},
+		{name: "scroll down", width: 10, height: 3, input: "a\nb\x1b[1T", want: "\na\nb"},
+		{name: "sequence split over writes", width: 10, height: 3, input: "a\x1b[", want: "a"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := NewScreen(c.width, c.height)
+			if _, err := s.Write([]byte(c.input)); err != nil {
+				t.Fatal(err)
+			}
+			if got := s.String(); got != c.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, c.want)
+			}
+		})
+	}
+
+	t.Run("sequence completed by next write", func(t *testing.T) {
+		s := NewScreen(10, 3)
+		s.Write([]byte("ab\x1b["))
+		s.Write([]byte("1DX"))
+
+		if got, want := s.String(), "aX"; got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+	})
+
+	t.Run("multibyte character split over writes", func(t *testing.T) {
+		s := NewScreen(10, 3)
+		s.Write([]byte{'a', 0xc3})
+		s.Write([]byte{0xa9})
+
+		if got, want := s.String(), "aé"; got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+	})
+}
+
+func TestScreen_StyledString(t *testing.T) {
+
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "unstyled", input: "ab", want: "ab"},
+		{name: "bold", input: "\x1b[1mab\x1b[0mc", want: "{1}ab{}c"},
+		{name: "reset without parameters", input: "\x1b[1ma\x1b[mb", want: "{1}a{}b"},
+		{name: "bold and color", input: "\x1b[1;32mok\x1b[0m", want: "{1;32}ok{}"},
+		{name: "normal intensity", input: "\x1b[1;2ma\x1b[22mb", want: "{1;2}a{}b"},
+		{name: "underline off", input: "\x1b[4ma\x1b[24mb", want: "{4}a{}b"},
+		{name: "default foreground", input: "\x1b[31ma\x1b[39mb", want: "{31}a{}b"},
+		{name: "bright background", input: "\x1b[101ma\x1b[49mb", want: "{101}a{}b"},
+		{name: "256 colors", input: "\x1b[38;5;208ma", want: "{38;5;208}a{}"},
+		{name: "true color background", input: "\x1b[48;2;1;2;3ma", want: "{48;2;1;2;3}a{}"},
+		{name: "blank cells keep style", input: "\x1b[7ma b\x1b[0m", want: "{7}a b{}"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := NewScreen(20, 2)
+			s.Write([]byte(c.input))
+
+			if got := s.StyledString(); got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestScreen_modes(t *testing.T) {
+
+	t.Run("cursor visibility", func(t *testing.T) {
+		s := NewScreen(10, 3)
+
+		s.Write([]byte("\x1b[?25l"))
+		if s.CursorVisible() {
+			t.Error("expected cursor hidden")
+		}
+
+		s.Write([]byte("\x1b[?25h"))
+		if !s.CursorVisible() {
+			t.Error("expected cursor visible")
+		}
+	})
+
+	t.Run("alternate screen", func(t *testing.T) {
+		s := NewScreen(10, 3)
+		s.Write([]byte("main\x1b[?1049h"))
+
+		if !s.Mode(1049) {
+			t.Error("expected mode 1049 enabled")
+		}
+		if got := s.String(); got != "" {
+			t.Errorf("got %q; want empty alternate screen", got)
+		}
+
+		s.Write([]byte("\x1b[Halt"))
+		if got := s.String(); got != "alt" {
+			t.Errorf("got %q; want %q", got, "alt")
+		}
+
+		s.Write([]byte("\x1b[?1049lX"))
+		if got := s.String(); got != "mainX" {
+			t.Errorf("got %q; want main screen with cursor restored", got)
+		}
+	})
+
+	t.Run("raw mode line feed", func(t *testing.T) {
+		s := NewScreen(10, 3)
+		s.setRaw(true)
+		s.Write([]byte("ab\ncd"))
+
+		if got, want := s.String(), "ab\n  cd"; got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+	})
+
+	t.Run("resize keeps what fits", func(t *testing.T) {
+		s := NewScreen(10, 3)
+		s.Write([]byte("abcdef\n12"))
+		s.resize(4, 1)
+
+		if got, want := s.String(), "abcd"; got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+		if row, col := s.Cursor(); row != 0 || col != 2 {
+			t.Errorf("got cursor %d,%d; want 0,2", row, col)
+		}
+	})
+}
+
+func TestScreen_replies(t *testing.T) {
+
+	cases := []struct {
+		name       string
+		background bool
+		input      string
+		want       []string
+	}{
+		{name: "cursor position", input: "ab\n\x1b[6n", want: []string{"\x1b[2;1R"}},
+		{name: "device attributes", input: "\x1b[c", want: []string{"\x1b[?62c"}},
+		{name: "background color", background: true, input: "\x1b]11;?\x1b\\",
+			want: []string{"\x1b]11;rgb:1010/2020/3030\x1b\\"}},
+		{name: "background color not answered", input: "\x1b]11;?\x07"},
+		{name: "private status not answered", input: "\x1b[?6n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := NewScreen(10, 3)
+			if c.background {
+				s.SetBackgroundColor(0x10, 0x20, 0x30)
+			}
+
+			var got []string
+			s.reply = func(r string) { got = append(got, r) }
+			s.Write([]byte(c.input))
+
+			if len(got) != len(c.want) {
+				t.Fatalf("got replies %q; want %q", got, c.want)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Errorf("got reply %q; want %q", got[i], c.want[i])
+				}
+			}
+		})
+	}
+}
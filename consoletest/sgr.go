@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package consoletest
+
+import (
+	"strconv"
+	"strings"
+)
+
+// sgrState is the graphic rendition set using Select Graphic Rendition
+// sequences, such as bold and the foreground and background colors.
+type sgrState struct {
+	attrs [10]bool
+	fg    string
+	bg    string
+}
+
+func (st *sgrState) apply(params []int) {
+
+	if len(params) == 0 {
+		*st = sgrState{}
+		return
+	}
+
+	for i := 0; i < len(params); i++ {
+		switch p := params[i]; {
+		case p == 0:
+			*st = sgrState{}
+		case p >= 1 && p <= 9:
+			st.attrs[p] = true
+		case p == 22:
+			st.attrs[1], st.attrs[2] = false, false
+		case p >= 23 && p <= 29:
+			st.attrs[p-20] = false
+		case p >= 30 && p <= 37, p >= 90 && p <= 97:
+			st.fg = strconv.Itoa(p)
+		case p == 39:
+			st.fg = ""
+		case p >= 40 && p <= 47, p >= 100 && p <= 107:
+			st.bg = strconv.Itoa(p)
+		case p == 49:
+			st.bg = ""
+		case p == 38 || p == 48:
+			color, n := extendedColor(p, params[i+1:])
+			if p == 38 {
+				st.fg = color
+			} else {
+				st.bg = color
+			}
+			i += n
+		}
+	}
+}
+
+// extendedColor returns the 256-color or RGB color starting with base, and the
+// number of parameters it used.
+func extendedColor(base int, params []int) (string, int) {
+
+	var n int
+	switch {
+	case len(params) >= 2 && params[0] == 5:
+		n = 2
+	case len(params) >= 4 && params[0] == 2:
+		n = 4
+	default:
+		return "", len(params)
+	}
+
+	parts := []string{strconv.Itoa(base)}
+	for _, p := range params[:n] {
+		parts = append(parts, strconv.Itoa(p))
+	}
+
+	return strings.Join(parts, ";"), n
+}
+
+// String returns the state as SGR parameters, for example "1;32".
+func (st *sgrState) String() string {
+
+	var parts []string
+
+	for i, set := range st.attrs {
+		if set {
+			parts = append(parts, strconv.Itoa(i))
+		}
+	}
+
+	if st.fg != "" {
+		parts = append(parts, st.fg)
+	}
+	if st.bg != "" {
+		parts = append(parts, st.bg)
+	}
+
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,380 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package consoletest
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/golistic/console"
+)
+
+// Terminal is a console.Terminal driven by a script: keys are queued using Type
+// and handed to the widget one at a time, while whatever the widget writes is
+// interpreted by a virtual Screen.
+//
+// When the script is exhausted, reading from the Terminal returns io.EOF.
+type Terminal struct {
+	mu sync.Mutex
+
+	screen *Screen
+	output bytes.Buffer
+
+	steps        []step
+	raw          bool
+	isTerminal   bool
+	capabilities console.Capabilities
+	notify       map[chan<- struct{}]struct{}
+
+	// suspend is sent on when the widget is asked to suspend, after which
+	// onSuspend is called while it is suspended
+	suspend   map[chan<- struct{}]struct{}
+	onSuspend func()
+}
+
+var _ console.Terminal = (*Terminal)(nil)
+var _ console.InputWaiter = (*Terminal)(nil)
+var _ console.ResizeNotifier = (*Terminal)(nil)
+var _ console.Suspender = (*Terminal)(nil)
+var _ console.CapabilitiesReporter = (*Terminal)(nil)
+
+// step is part of the script: either input, or a function to call once all
+// input before it was read.
+type step struct {
+	input []byte
+	do    func()
+	// reply is set for the response to a query written by the widget
+	reply bool
+}
+
+// NewTerminal returns a Terminal with a blank screen of the given dimensions.
+// It reports to support Unicode and true colors, which can be changed using
+// SetCapabilities.
+func NewTerminal(width, height int) *Terminal {
+
+	t := &Terminal{
+		screen:     NewScreen(width, height),
+		isTerminal: true,
+		capabilities: console.Capabilities{
+			TTY:     true,
+			Color:   console.ColorTrue,
+			Unicode: true,
+		},
+		notify:  map[chan<- struct{}]struct{}{},
+		suspend: map[chan<- struct{}]struct{}{},
+	}
+
+	t.screen.reply = t.reply
+
+	return t
+}
+
+// Screen returns the virtual screen showing the output.
+func (t *Terminal) Screen() *Screen {
+
+	return t.screen
+}
+
+// Output returns everything written to the terminal.
+func (t *Terminal) Output() string {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.output.String()
+}
+
+// Raw returns whether the terminal is in raw mode.
+func (t *Terminal) Raw() bool {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.raw
+}
+
+// SetIsTerminal sets whether the Terminal reports to be interactive.
+func (t *Terminal) SetIsTerminal(isTerminal bool) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.isTerminal = isTerminal
+}
+
+// SetCapabilities sets the capabilities the Terminal reports.
+func (t *Terminal) SetCapabilities(caps console.Capabilities) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.capabilities = caps
+}
+
+// Capabilities returns the capabilities of the terminal.
+func (t *Terminal) Capabilities() console.Capabilities {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.capabilities
+}
+
+// Type queues keys to be read by the widget. Each key is read separately, so
+// it can be a single character or a complete escape sequence such as Down.
+func (t *Terminal) Type(keys ...string) *Terminal {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, k := range keys {
+		if k == "" {
+			continue
+		}
+		t.steps = append(t.steps, step{input: []byte(k)})
+	}
+
+	return t
+}
+
+// TypeText queues the characters of text to be read one by one.
+func (t *Terminal) TypeText(text string) *Terminal {
+
+	for _, r := range text {
+		t.Type(string(r))
+	}
+
+	return t
+}
+
+// Do queues f to be called once the keys queued before it were read, for
+// example to take a snapshot of the screen while the widget is waiting.
+func (t *Terminal) Do(f func()) *Terminal {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.steps = append(t.steps, step{do: f})
+
+	return t
+}
+
+// Resize queues resizing the screen once the keys queued before it were read.
+func (t *Terminal) Resize(width, height int) *Terminal {
+
+	return t.Do(func() {
+		t.screen.resize(width, height)
+		t.send(t.notify)
+	})
+}
+
+// NotifyResize sends on ch each time the screen is resized using Resize.
+func (t *Terminal) NotifyResize(ch chan<- struct{}) func() {
+
+	return t.register(t.notify, ch)
+}
+
+// RequestSuspend queues asking the widget to suspend the process, like
+// SIGTSTP does, once the keys queued before it were read.
+func (t *Terminal) RequestSuspend() *Terminal {
+
+	return t.Do(func() {
+		t.send(t.suspend)
+	})
+}
+
+// OnSuspend sets f to be called while the widget has suspended the process,
+// for example to take a snapshot of the screen. When f returns, the widget
+// continues like after SIGCONT.
+func (t *Terminal) OnSuspend(f func()) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onSuspend = f
+}
+
+// NotifySuspend sends on ch each time suspending is requested using
+// RequestSuspend.
+func (t *Terminal) NotifySuspend(ch chan<- struct{}) func() {
+
+	return t.register(t.suspend, ch)
+}
+
+// Suspend calls the function set using OnSuspend.
+func (t *Terminal) Suspend() {
+
+	t.mu.Lock()
+	f := t.onSuspend
+	t.mu.Unlock()
+
+	if f != nil {
+		f()
+	}
+}
+
+// register adds ch to the channels in chans, until the returned function is
+// called.
+func (t *Terminal) register(chans map[chan<- struct{}]struct{}, ch chan<- struct{}) func() {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	chans[ch] = struct{}{}
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		delete(chans, ch)
+	}
+}
+
+// send sends on the channels in chans without blocking.
+func (t *Terminal) send(chans map[chan<- struct{}]struct{}) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for ch := range chans {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Read reads the next key queued using Type.
+func (t *Terminal) Read(p []byte) (int, error) {
+
+	t.runSteps()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.steps) == 0 {
+		return 0, io.EOF
+	}
+
+	st := &t.steps[0]
+	n := copy(p, st.input)
+	st.input = st.input[n:]
+	if len(st.input) == 0 {
+		t.steps = t.steps[1:]
+	}
+
+	return n, nil
+}
+
+// WaitInput reports whether a key is queued. When the script is exhausted, it
+// also returns true so that the next Read reports io.EOF. When a function queued
+// using Do or Resize is next, it is called and false is returned, so that the
+// widget handles what it caused before continuing.
+func (t *Terminal) WaitInput(time.Duration) (bool, error) {
+
+	if t.runStep() {
+		return false, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.steps) == 0 {
+		return true, nil
+	}
+
+	return t.steps[0].input != nil, nil
+}
+
+// runSteps calls the functions queued using Do which are next in the script.
+func (t *Terminal) runSteps() {
+
+	for t.runStep() {
+	}
+}
+
+// runStep calls the function queued using Do when it is next in the script,
+// and returns whether it did.
+func (t *Terminal) runStep() bool {
+
+	t.mu.Lock()
+	if len(t.steps) == 0 || t.steps[0].do == nil {
+		t.mu.Unlock()
+		return false
+	}
+	do := t.steps[0].do
+	t.steps = t.steps[1:]
+	t.mu.Unlock()
+
+	do()
+
+	return true
+}
+
+// reply queues the response to a query written by the widget in front of the
+// script, after the responses to earlier queries, like a terminal answers
+// immediately.
+func (t *Terminal) reply(response string) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	i := 0
+	for i < len(t.steps) && t.steps[i].reply {
+		i++
+	}
+
+	t.steps = append(t.steps[:i], append([]step{{input: []byte(response), reply: true}}, t.steps[i:]...)...)
+}
+
+// Write writes p to the screen.
+func (t *Terminal) Write(p []byte) (int, error) {
+
+	t.mu.Lock()
+	t.output.Write(p)
+	t.mu.Unlock()
+
+	return t.screen.Write(p)
+}
+
+// Size returns the dimensions of the screen.
+func (t *Terminal) Size() (width, height int, err error) {
+
+	width, height = t.screen.Size()
+	return width, height, nil
+}
+
+// IsTerminal returns whether the terminal is interactive, which is true
+// unless changed using SetIsTerminal.
+func (t *Terminal) IsTerminal() bool {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.isTerminal
+}
+
+// MakeRaw puts the terminal in raw mode, in which a line feed does not return
+// the cursor to the first column.
+func (t *Terminal) MakeRaw() (func() error, error) {
+
+	t.setRaw(true)
+
+	return func() error {
+		t.setRaw(false)
+		return nil
+	}, nil
+}
+
+func (t *Terminal) setRaw(raw bool) {
+
+	t.mu.Lock()
+	t.raw = raw
+	t.mu.Unlock()
+
+	t.screen.setRaw(raw)
+}
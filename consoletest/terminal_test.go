@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package consoletest
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestTerminal_script(t *testing.T) {
+
+	term := NewTerminal(20, 3)
+
+	var done []string
+	term.Type("a", Down).
+		Do(func() { done = append(done, "after down") }).
+		TypeText("bc")
+
+	read := func() string {
+		t.Helper()
+		p := make([]byte, 16)
+		n, err := term.Read(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(p[:n])
+	}
+
+	for _, want := range []string{"a", Down} {
+		if got := read(); got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+	}
+	if len(done) != 0 {
+		t.Error("expected Do not to run before the next read")
+	}
+
+	if got := read(); got != "b" {
+		t.Errorf("got %q; want %q", got, "b")
+	}
+	if len(done) != 1 {
+		t.Error("expected Do to run before reading the next key")
+	}
+
+	if got := read(); got != "c" {
+		t.Errorf("got %q; want %q", got, "c")
+	}
+
+	if _, err := term.Read(make([]byte, 16)); !errors.Is(err, io.EOF) {
+		t.Errorf("got error %v; want io.EOF at end of script", err)
+	}
+}
+
+func TestTerminal_WaitInput(t *testing.T) {
+
+	term := NewTerminal(20, 3)
+
+	var called bool
+	term.Do(func() { called = true }).Type("x")
+
+	if ok, _ := term.WaitInput(0); ok {
+		t.Error("expected no input while a Do step is next")
+	}
+	if !called {
+		t.Error("expected WaitInput to run the Do step")
+	}
+
+	if ok, _ := term.WaitInput(0); !ok {
+		t.Error("expected input when a key is next")
+	}
+
+	term.Read(make([]byte, 16))
+
+	if ok, _ := term.WaitInput(0); !ok {
+		t.Error("expected input at end of script, so the next read fails")
+	}
+}
+
+func TestTerminal_replies(t *testing.T) {
+
+	term := NewTerminal(20, 3)
+	term.Type("x")
+
+	// queries are answered before the script, in the order they were written
+	term.Write([]byte("ab\x1b[6n\x1b[c"))
+
+	var got []string
+	for range 3 {
+		p := make([]byte, 16)
+		n, err := term.Read(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, string(p[:n]))
+	}
+
+	want := []string{"\x1b[1;3R", "\x1b[?62c", "x"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("read %d: got %q; want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTerminal_Resize(t *testing.T) {
+
+	term := NewTerminal(20, 3)
+
+	ch := make(chan struct{}, 1)
+	stop := term.NotifyResize(ch)
+	defer stop()
+
+	term.Resize(30, 5).Type("x")
+	term.Read(make([]byte, 16))
+
+	select {
+	case <-ch:
+	default:
+		t.Error("expected resize notification")
+	}
+
+	if w, h, _ := term.Size(); w != 30 || h != 5 {
+		t.Errorf("got size %dx%d; want 30x5", w, h)
+	}
+}
+
+func TestTerminal_MakeRaw(t *testing.T) {
+
+	term := NewTerminal(20, 3)
+
+	restore, err := term.MakeRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !term.Raw() {
+		t.Error("expected raw mode")
+	}
+
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+	if term.Raw() {
+		t.Error("expected raw mode to be restored")
+	}
+}
+
+func TestKeys(t *testing.T) {
+
+	cases := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "ctrl+a", got: Ctrl('a'), want: "\x01"},
+		{name: "ctrl+Z", got: Ctrl('Z'), want: CtrlZ},
+		{name: "alt+x", got: Alt("x"), want: "\x1bx"},
+		{name: "click", got: Click(3, 4), want: "\x1b[<0;3;4M\x1b[<0;3;4m"},
+		{name: "wheel up", got: WheelUp(1, 2), want: "\x1b[<64;1;2M"},
+		{name: "wheel down", got: WheelDown(1, 2), want: "\x1b[<65;1;2M"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.got != c.want {
+				t.Errorf("got %q; want %q", c.got, c.want)
+			}
+		})
+	}
+}
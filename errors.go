@@ -7,5 +7,7 @@ package console
 import "errors"
 
 var (
-	ErrAborted = errors.New("aborted")
+	ErrAborted     = errors.New("aborted")
+	ErrNotTerminal = errors.New("not a terminal")
+	ErrNoOptions   = errors.New("no options to choose from")
 )
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Fallback defines what a widget does when its terminal is not interactive,
+// for example when input is piped or when running in CI.
+type Fallback int
+
+const (
+	// FallbackLines falls back to prompts reading a line of input, like
+	// entering the number of an option.
+	FallbackLines Fallback = iota
+	// FallbackError makes the widget return ErrNotTerminal.
+	FallbackError
+)
+
+// readLine reads a line from r without reading past the line feed, so that
+// what follows is left for the next prompt. The trailing line feed and any
+// carriage return are removed. When r is exhausted, the last line is returned
+// even when not terminated, or io.EOF when there is nothing left.
+func readLine(r io.Reader) (string, error) {
+
+	var line []byte
+	b := make([]byte, 1)
+
+	for {
+		n, err := r.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+			continue
+		}
+
+		if errors.Is(err, io.EOF) && len(line) > 0 {
+			break
+		}
+
+		if err == nil {
+			err = io.ErrNoProgress
+		}
+
+		return "", fmt.Errorf("reading input (%w)", err)
+	}
+
+	return strings.TrimRight(string(line), "\r"), nil
+}
+
+// promptLine writes prompt and reads answers, echoing them, until choose
+// accepts one and returns the index it chose. When choose does not accept the
+// answer, the message it returns is shown before prompting again.
+func promptLine(t Terminal, prompt string, choose func(answer string) (int, string)) (int, error) {
+
+	for {
+		fmt.Fprint(t, prompt)
+
+		// input is not echoed when not reading from a terminal
+		answer, err := readLine(t)
+		fmt.Fprintln(t, answer)
+		if err != nil {
+			return -1, err
+		}
+
+		index, msg := choose(strings.TrimSpace(answer))
+		if index >= 0 {
+			return index, nil
+		}
+
+		fmt.Fprintln(t, msg)
+	}
+}
@@ -4,14 +4,22 @@
 
 package console
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 func NewForm() *Form {
-	return &Form{}
+	return &Form{
+		theme: defaultThemeName(),
+	}
 }
 
 func NewFormWithScanner(scanner func(value any, dest any) error) *Form {
 	return &Form{
 		scanner: scanner,
-		theme:   "ascii",
+		theme:   defaultThemeName(),
 	}
 }
 
@@ -19,9 +27,23 @@ type Form struct {
 	Elements []FormElementer
 	scanner  func(value any, dest any) error
 
-	maxLengthLabel int
-	theme          Theme
-	shownLines     int
+	maxLabelWidth int
+	theme         Theme
+	variant       Theme
+	terminal      Terminal
+	fallback      Fallback
+	mouse         bool
+	altScreen     bool
+	retry         bool
+
+	// frame shows the answers, info and errors on interactive terminals,
+	// so they can be redrawn and cleared however the lines wrap
+	frame *frame
+	lines []string
+
+	// input was typed while asking the terminal for its background, and is
+	// read first by the next element
+	input []byte
 }
 
 func (f *Form) SetTheme(theme Theme) *Form {
@@ -29,6 +51,50 @@ func (f *Form) SetTheme(theme Theme) *Form {
 	return f
 }
 
+// SetTerminal sets the Terminal used by all elements of the form. When not set,
+// the standard input and output of the process is used.
+func (f *Form) SetTerminal(t Terminal) *Form {
+	f.terminal = t
+	return f
+}
+
+// SetFallback sets what the form does when the terminal is not interactive.
+// By default, elements prompt for a line of input.
+func (f *Form) SetFallback(fallback Fallback) *Form {
+	f.fallback = fallback
+	return f
+}
+
+// SetMouse sets whether the mouse can be used to choose options.
+func (f *Form) SetMouse(enabled bool) *Form {
+	f.mouse = enabled
+	return f
+}
+
+// SetAltScreen sets whether the form is shown full-screen, using the alternate
+// screen buffer of the terminal. What was shown before is restored when the
+// form is done, so Clear is not needed.
+func (f *Form) SetAltScreen(enabled bool) *Form {
+	f.altScreen = enabled
+	return f
+}
+
+// SetRetry sets whether an element is asked again, showing the error, when
+// one of its validators fails. By default, Execute returns the error.
+func (f *Form) SetRetry(enabled bool) *Form {
+	f.retry = enabled
+	return f
+}
+
+func (f *Form) getTerminal() Terminal {
+
+	if f.terminal == nil {
+		return StdTerminal()
+	}
+
+	return f.terminal
+}
+
 func (f *Form) AddElements(elements ...FormElementer) {
 
 	f.Elements = []FormElementer{}
@@ -41,22 +107,48 @@ func (f *Form) AddElements(elements ...FormElementer) {
 
 func (f *Form) Execute() error {
 
-	for _, elm := range f.Elements {
-		l := len(elm.Label())
-		if l > f.maxLengthLabel {
-			f.maxLengthLabel = l
+	t := f.getTerminal()
+
+	if !t.IsTerminal() {
+		if f.fallback == FallbackError {
+			return ErrNotTerminal
 		}
+	} else if f.altScreen {
+		screen := newAlternateScreen(t)
+		defer screen.close()
+	}
+
+	f.input = nil
+	f.variant = f.themeVariant(t)
+
+	f.frame = nil
+	f.lines = nil
+	if t.IsTerminal() {
+		f.frame = &frame{w: t}
+	}
+
+	// elements can be replaced between executions
+	f.maxLabelWidth = 0
+	for _, elm := range f.Elements {
+		f.maxLabelWidth = max(f.maxLabelWidth, Width(elm.Label()))
 	}
 
 	for _, element := range f.Elements {
-		if err := element.do(); err != nil {
-			return err
-		}
+		for {
+			if err := element.do(); err != nil {
+				return err
+			}
 
-		for _, validator := range element.getValidators() {
-			if err := validator(element.Value()); err != nil {
+			err := f.validate(element)
+			if err == nil {
+				break
+			}
+			if !f.retry {
 				return err
 			}
+
+			// ask again, showing why the answer was not valid
+			f.showError(err)
 		}
 
 		if fb, ok := element.(FormCallbacker); ok {
@@ -67,8 +159,180 @@ func (f *Form) Execute() error {
 	return nil
 }
 
+// validate returns the first error returned by the validators of element.
+func (f *Form) validate(element FormElementer) error {
+
+	for _, validator := range element.getValidators() {
+		if err := validator(element.Value()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// themeSpec returns the theme used for the elements, and the capabilities of
+// the terminal it is shown on.
+func (f *Form) themeSpec() (FormTheme, Capabilities) {
+
+	caps := DetectCapabilities(f.getTerminal())
+	return resolveTheme(f.currentTheme(), caps).Form, caps
+}
+
+// currentTheme returns the theme used for the elements, which is the light or
+// dark variant of the theme of the form when it has them.
+func (f *Form) currentTheme() Theme {
+
+	if f.variant != "" {
+		return f.variant
+	}
+
+	return f.theme
+}
+
+// themeVariant returns the variant of the theme of the form which matches the
+// background of terminal t.
+func (f *Form) themeVariant(t Terminal) Theme {
+
+	spec, ok := themeSpec(f.theme)
+	if !ok || (spec.Light == "" && spec.Dark == "") || !t.IsTerminal() {
+		return f.theme
+	}
+
+	// asking the terminal needs raw mode; keys pressed meanwhile are passed
+	// on to the first element
+	sn, err := newSession(t, sessionOptions{})
+	if err != nil {
+		return f.theme
+	}
+	defer sn.close()
+
+	variant := sn.themeVariant(f.theme)
+	f.input = sn.unread()
+
+	return variant
+}
+
+// takeInput returns the input typed while asking the terminal for its
+// background, which the element asking for input next must read first.
+func (f *Form) takeInput() []byte {
+
+	input := f.input
+	f.input = nil
+
+	return input
+}
+
+// label returns label padded to the width of the widest label and followed
+// by the marker, styled using the theme.
+func (f *Form) label(label string) string {
+
+	theme, caps := f.themeSpec()
+
+	marker := theme.Marker
+	if marker == "" {
+		marker = ": "
+	}
+
+	return Pad(theme.Label.RenderFor(caps, label), f.maxLabelWidth) +
+		theme.Prompt.RenderFor(caps, marker)
+}
+
+// bareLabel returns label styled using the theme, without padding and marker,
+// for widgets which separate the label from the options themselves.
+func (f *Form) bareLabel(label string) string {
+
+	theme, caps := f.themeSpec()
+	return theme.Label.RenderFor(caps, label)
+}
+
+// hint returns how the default value is shown, or an empty string when
+// there is none.
+func (f *Form) hint(value string) string {
+
+	if value == "" {
+		return ""
+	}
+
+	theme, caps := f.themeSpec()
+	return theme.Hint.RenderFor(caps, "["+value+"]") + " "
+}
+
+// answer returns the line showing the answer of the element with the given label.
+func (f *Form) answer(label, value string) string {
+
+	theme, caps := f.themeSpec()
+	return f.label(label) + theme.Input.RenderFor(caps, value)
+}
+
+// echo shows the answer of the element with the given label, which is only
+// done for interactive terminals; otherwise the answer is already shown.
+func (f *Form) echo(label, value string) {
+
+	if f.frame == nil {
+		return
+	}
+
+	f.show(f.answer(label, value))
+}
+
+// info shows text using the theme, returning the number of lines it takes in
+// the frame.
+func (f *Form) info(text string) int {
+
+	theme, caps := f.themeSpec()
+	return f.show(theme.Info.RenderFor(caps, text))
+}
+
+// showError shows the error returned by a validator.
+func (f *Form) showError(err error) {
+
+	theme, caps := f.themeSpec()
+	f.show(theme.Error.RenderFor(caps, err.Error()))
+}
+
+// show adds text below what the form showed, returning the number of lines
+// it takes. Lines too wide for the terminal are wrapped, so the frame knows
+// where each of them is. When the terminal is not interactive, text is
+// written as is, and 0 is returned.
+func (f *Form) show(text string) int {
+
+	t := f.getTerminal()
+	if f.frame == nil {
+		fmt.Fprintln(t, text)
+		return 0
+	}
+
+	width, _ := terminalSize(t)
+
+	n := len(f.lines)
+	for _, line := range strings.Split(text, "\n") {
+		if Width(line) < width {
+			f.lines = append(f.lines, line)
+			continue
+		}
+		f.lines = append(f.lines, Wrap(line, width-1)...)
+	}
+	f.frame.render(f.lines)
+
+	return len(f.lines) - n
+}
+
+// remove removes n lines shown by the form, starting at line from.
+func (f *Form) remove(from, n int) {
+
+	f.lines = slices.Delete(f.lines, from, from+n)
+	f.frame.render(f.lines)
+}
+
 func (f *Form) Clear() {
-	ClearLines(f.shownLines + 1)
+
+	if f.altScreen || f.frame == nil {
+		return
+	}
+
+	f.frame.clear()
+	f.lines = nil
 }
 
 func (f *Form) RawValues() map[string]any {
@@ -5,7 +5,9 @@
 package console
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/ergochat/readline"
 )
@@ -28,25 +30,76 @@ var _ FormElementer = (*FormInput)(nil)
 
 func (fi *FormInput) do() error {
 
-	rl, err := readline.New("")
+	var defaultValue string
+	if fi.defaultValue != nil {
+		defaultValue = fmt.Sprintf("%v", fi.defaultValue(nil).Value)
+	}
+
+	prompt := fi.form.label(fi.label) + fi.form.hint(defaultValue)
+
+	t := fi.form.getTerminal()
+	if !t.IsTerminal() {
+		// readline does not show the prompt when not reading from a terminal
+		var line string
+		_, err := promptLine(t, prompt, func(answer string) (int, string) {
+			line = answer
+			return 0, ""
+		})
+		if err != nil {
+			return err
+		}
+
+		if line == "" {
+			line = defaultValue
+		}
+		fi.value = line
+
+		return fi.scan()
+	}
+
+	makeRaw, exitRaw := rawModeFuncs(t)
+
+	rl, err := readline.NewFromConfig(&readline.Config{
+		// what was typed while the form asked the terminal for its
+		// background comes first
+		Stdin:  io.MultiReader(bytes.NewReader(fi.form.takeInput()), t),
+		Stdout: t,
+		FuncGetSize: func() (int, int) {
+			return terminalSize(t)
+		},
+		FuncIsTerminal: t.IsTerminal,
+		FuncMakeRaw:    makeRaw,
+		FuncExitRaw:    exitRaw,
+	})
 	if err != nil {
 		return err
 	}
 	defer func() { _ = rl.Close() }()
 
-	rl.SetPrompt(fmt.Sprintf("%-*s: ", fi.form.maxLengthLabel, fi.label))
+	rl.SetPrompt(prompt)
 
-	var defaultValue string
-	if fi.defaultValue != nil {
-		defaultValue = fmt.Sprintf("%v", fi.defaultValue(nil).Value)
-	}
-
-	fi.value, err = rl.ReadLineWithDefault(defaultValue)
+	line, err := rl.ReadLine()
 	if err != nil {
 		return err
 	}
 
-	fi.form.shownLines += 1
+	// replace the prompt and what was typed, which can take more than one
+	// line, with the answer
+	width, _ := terminalSize(t)
+	fi.form.frame.written(1 + max(0, Width(prompt+line)-1)/max(1, width))
+
+	if line == "" {
+		line = defaultValue
+	}
+	fi.value = line
+
+	fi.form.echo(fi.label, line)
+
+	return fi.scan()
+}
+
+// scan stores the answer in the destination using the scanner of the form.
+func (fi *FormInput) scan() error {
 
 	if fi.form.scanner != nil {
 		return fi.form.scanner(fi.value.(string), fi.dest)
@@ -68,3 +121,26 @@ func (fi *FormInput) DefaultValue(f func(props *DefaultValueProps) DefaultValue)
 
 	return fi
 }
+
+// rawModeFuncs returns the functions readline uses to enter and exit
+// raw mode on the terminal t.
+func rawModeFuncs(t Terminal) (makeRaw, exitRaw func() error) {
+
+	var restore func() error
+
+	makeRaw = func() error {
+		var err error
+		restore, err = t.MakeRaw()
+		return err
+	}
+
+	exitRaw = func() error {
+		if restore == nil {
+			return nil
+		}
+		defer func() { restore = nil }()
+		return restore()
+	}
+
+	return makeRaw, exitRaw
+}
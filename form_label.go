@@ -4,8 +4,6 @@
 
 package console
 
-import "fmt"
-
 func NewFormText(text string) *FormText {
 	return &FormText{
 		text:        text,
@@ -22,6 +20,6 @@ var _ FormElementer = (*FormInput)(nil)
 
 func (ft *FormText) do() error {
 
-	fmt.Printf("%s\n", ft.text)
+	ft.form.info(ft.text)
 	return nil
 }
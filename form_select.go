@@ -4,10 +4,7 @@
 
 package console
 
-import (
-	"fmt"
-	"strings"
-)
+import "fmt"
 
 type SelectProps struct {
 	Options          []string
@@ -17,6 +14,20 @@ type SelectProps struct {
 	InfoText         string
 	OptionsAndValues func() ([]string, []any, error)
 	Callback         func(value any) string
+	Overflow         Overflow
+	// Items are shown instead of Options and Values when not empty, and
+	// Groups instead of both.
+	Items        []Option[any]
+	Groups       []Group[any]
+	Descriptions DescriptionPlacement
+	// Preview computes the text previewing the value of the option with the
+	// pointer, shown as set by PreviewPlacement.
+	Preview          func(value any) string
+	PreviewPlacement PreviewPlacement
+	// Source provides the options when there are too many to know up front,
+	// and is used instead of all of the above. DefaultValue is not used with
+	// a Source.
+	Source OptionSource[any]
 }
 
 func NewFormSelect(name, label string, dest any, props SelectProps) *FormSelect {
@@ -34,7 +45,10 @@ func NewFormSelect(name, label string, dest any, props SelectProps) *FormSelect
 type FormSelect struct {
 	*formElement
 
-	props SelectProps
+	props  SelectProps
+	answer string
+	// infoLines is the number of lines the info text takes above the answer
+	infoLines int
 }
 
 var _ FormElementer = (*FormSelect)(nil)
@@ -46,7 +60,7 @@ func (fs *FormSelect) setForm(form *Form) {
 
 func (fs *FormSelect) do() error {
 
-	if fs.props.OptionsAndValues != nil {
+	if fs.props.OptionsAndValues != nil && fs.props.Source == nil {
 		var err error
 		fs.props.Options, fs.props.Values, err = fs.props.OptionsAndValues()
 		if err != nil {
@@ -54,19 +68,40 @@ func (fs *FormSelect) do() error {
 		}
 	}
 
-	var clearLines int
+	fs.infoLines = 0
 	if fs.props.InfoText != "" {
-		fmt.Println(fs.props.InfoText)
-		clearLines = 1 + strings.Count(fs.props.InfoText, "\n")
+		fs.infoLines = fs.form.info(fs.props.InfoText)
 	}
 
-	selection, err := NewSelection(fs.props.Options, fs.props.Values)
+	t := fs.form.getTerminal()
+	if !t.IsTerminal() {
+		// the options are listed below the label, which is otherwise only
+		// shown with the answer
+		fmt.Fprintln(t, fs.form.label(fs.label))
+	}
+
+	var selection *Selection[any]
+	var err error
+	switch {
+	case fs.props.Source != nil:
+		selection, err = NewSelectionFromSource(fs.props.Source)
+	case len(fs.props.Groups) > 0:
+		selection, err = NewGroupedSelection(fs.props.Groups)
+	case len(fs.props.Items) > 0:
+		selection, err = NewSelectionFromOptions(fs.props.Items)
+	default:
+		selection, err = NewSelection(fs.props.Options, fs.props.Values)
+	}
 	if err != nil {
 		return err
 	}
+	selection.SetTerminal(t)
+	selection.SetFallback(fs.form.fallback)
+	selection.SetMouse(fs.form.mouse)
+	selection.input = fs.form.takeInput()
 
 	if fs.defaultValue != nil {
-		for p, v := range fs.props.Values {
+		for p, v := range selection.values {
 			if fs.defaultValue(&DefaultValueProps{SelectOption: v}).Found {
 				selection.SetSelected(p)
 				break
@@ -77,12 +112,17 @@ func (fs *FormSelect) do() error {
 	if fs.props.Showing > 0 {
 		selection.SetShowing(fs.props.Showing)
 	}
+	selection.SetOverflow(fs.props.Overflow)
+	selection.SetDescriptionPlacement(fs.props.Descriptions)
+	selection.SetPreview(fs.props.Preview)
+	selection.SetPreviewPlacement(fs.props.PreviewPlacement)
 
-	if err := selection.RenderWithTheme(fs.form.theme); err != nil {
+	if err := selection.RenderWithTheme(fs.form.currentTheme()); err != nil {
 		return err
 	}
 
 	fs.value = selection.Selected()
+	fs.answer = selection.SelectedOption()
 
 	if fs.form.scanner != nil {
 		if err := fs.form.scanner(fs.value, fs.dest); err != nil {
@@ -90,7 +130,7 @@ func (fs *FormSelect) do() error {
 		}
 	}
 
-	fs.form.shownLines += clearLines
+	fs.form.echo(fs.label, fs.answer)
 
 	return nil
 }
@@ -110,11 +150,16 @@ func (fs *FormSelect) DefaultValue(f func(props *DefaultValueProps) DefaultValue
 }
 
 func (fs *FormSelect) Callback() {
-	if fs.props.Callback != nil {
-		if fs.props.InfoText != "" {
-			ClearLines(2 + strings.Count(fs.props.InfoText, "\n"))
-		}
 
-		fmt.Println(fs.props.Callback(fs.value))
+	if fs.props.Callback == nil {
+		return
 	}
+
+	if fs.infoLines > 0 {
+		// replace the info text with what the callback returns, keeping
+		// the answer
+		fs.form.remove(len(fs.form.lines)-1-fs.infoLines, fs.infoLines)
+	}
+
+	fs.form.show(fs.props.Callback(fs.value))
 }
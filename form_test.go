@@ -0,0 +1,247 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golistic/console"
+	"github.com/golistic/console/consoletest"
+)
+
+var errNotYes = errors.New("answer yes")
+
+func newYesForm(t *testing.T, term *consoletest.Terminal) (*console.Form, *console.FormInput) {
+
+	t.Helper()
+
+	input := console.NewFormInput("answer", "Answer", nil)
+	input.AddValidator(func(value any) error {
+		if value != "yes" {
+			return errNotYes
+		}
+		return nil
+	})
+
+	form := console.NewForm().SetTerminal(term)
+	form.AddElements(input)
+
+	return form, input
+}
+
+func TestForm_Execute_validation(t *testing.T) {
+
+	t.Run("returns error", func(t *testing.T) {
+		term := consoletest.NewTerminal(40, 10)
+		term.SetIsTerminal(false)
+		term.TypeText("no\nyes\n")
+
+		form, _ := newYesForm(t, term)
+		if err := form.Execute(); !errors.Is(err, errNotYes) {
+			t.Fatalf("got error %v; want %v", err, errNotYes)
+		}
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		term := consoletest.NewTerminal(40, 10)
+		term.SetIsTerminal(false)
+		term.TypeText("no\nyes\n")
+
+		form, input := newYesForm(t, term)
+		form.SetRetry(true)
+		if err := form.Execute(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := input.Value(); got != "yes" {
+			t.Errorf("got %v; want yes", got)
+		}
+	})
+}
+
+func TestForm_lines(t *testing.T) {
+
+	term := consoletest.NewTerminal(40, 10)
+	term.SetIsTerminal(false)
+	term.TypeText("alice\n2\n")
+
+	var name, region string
+	form := console.NewFormWithScanner(func(value any, dest any) error {
+		*dest.(*string) = value.(string)
+		return nil
+	}).SetTerminal(term)
+	form.AddElements(
+		console.NewFormInput("name", "Name", &name),
+		console.NewFormSelect("region", "Region", &region, console.SelectProps{
+			Options: []string{"eu", "us"},
+			Values:  []any{"eu", "us"},
+		}),
+	)
+
+	if err := form.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Name  : alice\nRegion:\n 1) eu\n 2) us\nEnter number [1]: 2"
+	if got := term.Screen().String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if name != "alice" || region != "us" {
+		t.Errorf("got %q and %q; want alice and us", name, region)
+	}
+}
+
+func TestForm_toggleLines(t *testing.T) {
+
+	term := consoletest.NewTerminal(40, 10)
+	term.SetIsTerminal(false)
+	term.TypeText("\n")
+
+	var ok bool
+	toggle := console.NewFormToggleBool("ok", "OK?", &ok, true)
+
+	form := console.NewForm().SetTerminal(term)
+	form.AddElements(toggle)
+
+	if err := form.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := term.Screen().String(), "OK? (Yes/No) [Yes]:"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	if got := toggle.Value(); got != true {
+		t.Errorf("got %v; want true", got)
+	}
+}
+
+func TestForm_wrappedInput(t *testing.T) {
+
+	term := consoletest.NewTerminal(20, 10)
+	term.TypeText("a rather long name which wraps\r")
+	term.TypeText("yes\r")
+
+	form := console.NewForm().SetTerminal(term).SetTheme(console.ThemeAscii)
+	form.AddElements(
+		console.NewFormText("Tell us:"),
+		console.NewFormInput("name", "Name", nil),
+		console.NewFormInput("ok", "OK", nil),
+	)
+
+	if err := form.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Tell us:\nName: a rather long\nname which wraps\nOK  : yes"
+	if got := term.Screen().String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	form.Clear()
+	if got := term.Screen().String(); got != "" {
+		t.Errorf("got:\n%s\nwant nothing after clearing", got)
+	}
+}
+
+func TestForm_selectCallback(t *testing.T) {
+
+	term := consoletest.NewTerminal(30, 10)
+	term.Type(consoletest.Down, consoletest.Enter)
+
+	form := console.NewForm().SetTerminal(term).SetTheme(console.ThemeAscii)
+	form.AddElements(console.NewFormSelect("region", "Region", nil, console.SelectProps{
+		Options:  []string{"eu", "us"},
+		Values:   []any{"eu", "us"},
+		InfoText: "Where is your data\nstored?",
+		Callback: func(value any) string {
+			return "Stored in " + value.(string)
+		},
+	}))
+
+	if err := form.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Region: us\nStored in us"
+	if got := term.Screen().String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestForm_keysWhileQueryingBackground(t *testing.T) {
+
+	// Down is pressed before the terminal reports its background
+	input := "\033[B" + "\033]11;rgb:ffff/ffff/ffff\033\\" + "\033[?62c" + "\r"
+	term := console.NewStreamTerminal(strings.NewReader(input), io.Discard, 40, 10)
+
+	region := console.NewFormSelect("region", "Region", nil, console.SelectProps{
+		Options: []string{"eu", "us"},
+		Values:  []any{"eu", "us"},
+	})
+
+	form := console.NewForm().SetTerminal(term).SetTheme(console.ThemeColor01)
+	form.AddElements(region)
+
+	if err := form.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := region.Value(); got != "us" {
+		t.Errorf("got %v; want us", got)
+	}
+}
+
+func TestForm_labelWidth(t *testing.T) {
+
+	term := consoletest.NewTerminal(40, 10)
+	term.SetIsTerminal(false)
+	term.TypeText("alice\nbob\n")
+
+	form := console.NewForm().SetTerminal(term)
+	form.AddElements(console.NewFormInput("name", "Full name", nil))
+	if err := form.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	form.AddElements(console.NewFormInput("name", "Name", nil))
+	if err := form.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Full name: alice\nName: bob"
+	if got := term.Screen().String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestForm_theme(t *testing.T) {
+
+	term := consoletest.NewTerminal(40, 10)
+	term.TypeText("bob")
+	term.Do(func() {
+		// the default value is shown as hint while typing
+		if got, want := term.Screen().StyledString(), "{1}Name{}: {90}[alice]{} bob"; got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+	})
+	term.Type(consoletest.Enter)
+
+	name := console.NewFormInput("name", "Name", nil)
+	name.DefaultValue(func(*console.DefaultValueProps) console.DefaultValue {
+		return console.DefaultValue{Value: "alice", Found: true}
+	})
+	form := console.NewForm().SetTerminal(term).SetTheme(console.ThemeColor01)
+	form.AddElements(name)
+	if err := form.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := term.Screen().StyledString(), "{1}Name{}: {32}bob{}"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
@@ -41,18 +41,23 @@ var _ FormElementer = (*FormToggle)(nil)
 
 func (ft *FormToggle) do() error {
 
-	toggle, err := NewToggle(ft.label, ft.props.Options, ft.props.Values)
+	toggle, err := NewToggle(ft.form.bareLabel(ft.label), ft.props.Options, ft.props.Values)
 	if err != nil {
 		return err
 	}
 
+	toggle.SetTerminal(ft.form.getTerminal())
+	toggle.SetFallback(ft.form.fallback)
+	toggle.SetMouse(ft.form.mouse)
+	toggle.input = ft.form.takeInput()
 	toggle.SetSelected(ft.props.DefaultValue)
 
-	if err := toggle.Render(); err != nil {
+	if err := toggle.RenderWithTheme(ft.form.currentTheme()); err != nil {
 		return err
 	}
 
 	ft.value = toggle.Selected()
+	ft.form.echo(ft.label, toggle.options[toggle.pointer])
 
 	if ft.form.scanner != nil {
 		return ft.form.scanner(ft.value.(bool), ft.dest)
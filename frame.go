@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// frame draws lines of text at the cursor, and redraws them by comparing with
+// what was drawn before, writing only the cells which changed using a single
+// write.
+type frame struct {
+	w io.Writer
+
+	// cells drawn, which is nil when what is on the screen is not known
+	cells [][]cell
+	// row of the cursor relative to the first line of the frame
+	row int
+	// inline keeps the cursor on the last line of the frame instead of moving
+	// it to the line below
+	inline bool
+}
+
+// cell is one column of the screen.
+type cell struct {
+	// text is empty for the second column of a wide character
+	text  string
+	style string
+}
+
+// render draws lines, which must fit the width of the terminal.
+func (f *frame) render(lines []string) {
+
+	fb := frameBuffer{row: f.row}
+
+	if f.cells == nil {
+		fb.moveTo(0)
+		fb.WriteString("\r\033[J")
+	}
+
+	cells := make([][]cell, len(lines))
+	for i, line := range lines {
+		cells[i] = parseCells(line)
+
+		var old []cell
+		if i < len(f.cells) {
+			old = f.cells[i]
+		}
+		fb.writeChanges(i, old, cells[i])
+	}
+
+	if len(f.cells) > len(lines) {
+		fb.moveTo(len(lines))
+		fb.WriteString("\r\033[J")
+	}
+
+	end := len(lines)
+	if f.inline {
+		end = max(0, end-1)
+	}
+	fb.moveTo(end)
+	fb.WriteString("\r")
+
+	f.cells = cells
+	f.row = end
+
+	f.w.Write(fb.Bytes())
+}
+
+// invalidate makes the next render draw everything, for example because the
+// terminal was resized.
+func (f *frame) invalidate() {
+
+	f.cells = nil
+}
+
+// written records that rows lines were written below the frame by something
+// else, such as readline echoing input, so the next render draws everything
+// again, replacing them.
+func (f *frame) written(rows int) {
+
+	f.row += rows
+	f.cells = nil
+}
+
+// clear erases the frame, leaving the cursor where the first line was.
+func (f *frame) clear() {
+
+	fb := frameBuffer{row: f.row}
+	fb.moveTo(0)
+	fb.WriteString("\r\033[J")
+
+	f.cells = nil
+	f.row = 0
+
+	f.w.Write(fb.Bytes())
+}
+
+// frameBuffer collects what is written for a frame, keeping track of the row
+// of the cursor.
+type frameBuffer struct {
+	bytes.Buffer
+	row int
+}
+
+func (fb *frameBuffer) moveTo(row int) {
+
+	switch {
+	case row < fb.row:
+		fmt.Fprintf(fb, "\033[%dA", fb.row-row)
+	case row > fb.row:
+		// line feeds scroll when at the bottom of the screen
+		fb.WriteString(strings.Repeat("\n", row-fb.row))
+	}
+
+	fb.row = row
+}
+
+// writeChanges writes the cells of row which differ from what was drawn.
+func (fb *frameBuffer) writeChanges(row int, old, cells []cell) {
+
+	for i := 0; i < len(cells); {
+		if i < len(old) && old[i] == cells[i] {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(cells) && (i >= len(old) || old[i] != cells[i]) {
+			i++
+		}
+		if cells[start].text == "" && start > 0 {
+			// second column of a wide character
+			start--
+		}
+
+		fb.moveTo(row)
+		fmt.Fprintf(fb, "\033[%dG", start+1)
+
+		var style string
+		for _, c := range cells[start:i] {
+			if c.text == "" {
+				continue
+			}
+			if c.style != style {
+				if style != "" {
+					fb.WriteString("\033[0m")
+				}
+				fb.WriteString(c.style)
+				style = c.style
+			}
+			fb.WriteString(c.text)
+		}
+		if style != "" {
+			fb.WriteString("\033[0m")
+		}
+	}
+
+	if len(cells) < len(old) {
+		fb.moveTo(row)
+		fmt.Fprintf(fb, "\033[%dG\033[K", len(cells)+1)
+	}
+}
+
+// parseCells splits line into the cells it takes on the screen, keeping the
+// SGR escape sequences styling each cell. Other escape sequences are dropped.
+func parseCells(line string) []cell {
+
+	var cells []cell
+	var m textMeasure
+	var style string
+
+	for len(line) > 0 {
+		if line[0] == 0x1b {
+			if loc := ansiSequence.FindStringIndex(line); loc != nil && loc[0] == 0 {
+				style = sgrActive(style, line[:loc[1]])
+				line = line[loc[1]:]
+				continue
+			}
+		}
+
+		r, size := utf8.DecodeRuneInString(line)
+		line = line[size:]
+
+		switch m.add(r) {
+		case 0:
+			// joins the character in the previous cell
+			if i := len(cells) - 1; i >= 0 {
+				if cells[i].text == "" && i > 0 {
+					i--
+				}
+				cells[i].text += string(r)
+			}
+		case 2:
+			cells = append(cells, cell{text: string(r), style: style}, cell{style: style})
+		default:
+			cells = append(cells, cell{text: string(r), style: style})
+		}
+	}
+
+	return cells
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestFrame_render(t *testing.T) {
+
+	var out bytes.Buffer
+	fr := &frame{w: &out}
+
+	steps := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{name: "first", lines: []string{"ab", "cd"}, want: "\r\033[J\033[1Gab\n\033[1Gcd\n\r"},
+		{name: "one cell changed", lines: []string{"ab", "cx"}, want: "\033[1A\033[2Gx\n\r"},
+		{name: "unchanged", lines: []string{"ab", "cx"}, want: "\r"},
+		{name: "line removed", lines: []string{"ab"}, want: "\033[1A\r\033[J\r"},
+		{name: "shorter line", lines: []string{"a"}, want: "\033[1A\033[2G\033[K\n\r"},
+	}
+
+	for _, st := range steps {
+		out.Reset()
+		fr.render(st.lines)
+		if got := out.String(); got != st.want {
+			t.Errorf("%s: got %q; want %q", st.name, got, st.want)
+		}
+	}
+
+	out.Reset()
+	fr.clear()
+	if got, want := out.String(), "\033[1A\r\033[J"; got != want {
+		t.Errorf("clear: got %q; want %q", got, want)
+	}
+}
+
+func TestFrame_invalidate(t *testing.T) {
+
+	var out bytes.Buffer
+	fr := &frame{w: &out}
+	fr.render([]string{"ab"})
+
+	out.Reset()
+	fr.invalidate()
+	fr.render([]string{"ab"})
+
+	if got, want := out.String(), "\033[1A\r\033[J\033[1Gab\n\r"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestParseCells(t *testing.T) {
+
+	cases := []struct {
+		name string
+		line string
+		want []cell
+	}{
+		{name: "plain", line: "ab", want: []cell{{text: "a"}, {text: "b"}}},
+		{name: "wide", line: "日a", want: []cell{{text: "日"}, {}, {text: "a"}}},
+		{name: "combining", line: "e\u0301", want: []cell{{text: "e\u0301"}}},
+		{name: "styled", line: "\033[1ma\033[0mb", want: []cell{{text: "a", style: "\033[1m"}, {text: "b"}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := parseCells(c.line); !slices.Equal(got, c.want) {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"strings"
+	"unicode"
+)
+
+// scores of a matched character; consecutive matches and matches at the start
+// of a word get a bonus
+const (
+	fuzzyMatchScore       = 1
+	fuzzyConsecutiveBonus = 4
+	fuzzyWordStartBonus   = 5
+)
+
+// fuzzyMatch returns whether text contains the characters of query in order,
+// ignoring case, together with a score which is higher for better matches and
+// the positions of the matched characters. Of all ways to match, the one with
+// the highest score is used.
+func fuzzyMatch(query, text string) (int, []int, bool) {
+
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, nil, true
+	}
+
+	t := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(t) {
+		// lowering changed the number of characters; compare one by one
+		lower = make([]rune, len(t))
+		for i, r := range t {
+			lower[i] = unicode.ToLower(r)
+		}
+	}
+
+	if len(q) > len(t) {
+		return 0, nil, false
+	}
+
+	// score[j][i] is the best score matching q[:j+1] with q[j] at t[i], or -1
+	// when not possible; from[j][i] is where q[j-1] is then matched
+	score := make([][]int, len(q))
+	from := make([][]int, len(q))
+
+	for j := range q {
+		score[j] = make([]int, len(t))
+		from[j] = make([]int, len(t))
+
+		// best score of q[:j] matched before i-1, and where
+		bestBefore, bestAt := -1, -1
+
+		for i := range t {
+			score[j][i] = -1
+
+			if j > 0 && i >= 2 && score[j-1][i-2] > bestBefore {
+				bestBefore, bestAt = score[j-1][i-2], i-2
+			}
+
+			if lower[i] != q[j] {
+				continue
+			}
+
+			base := fuzzyMatchScore
+			if wordStart(t, i) {
+				base += fuzzyWordStartBonus
+			}
+
+			if j == 0 {
+				score[j][i] = base
+				continue
+			}
+
+			if i > 0 && score[j-1][i-1] >= 0 {
+				score[j][i] = score[j-1][i-1] + base + fuzzyConsecutiveBonus
+				from[j][i] = i - 1
+			}
+			if bestBefore >= 0 && bestBefore+base > score[j][i] {
+				score[j][i] = bestBefore + base
+				from[j][i] = bestAt
+			}
+		}
+	}
+
+	last := len(q) - 1
+	best, at := -1, -1
+	for i, s := range score[last] {
+		if s > best {
+			best, at = s, i
+		}
+	}
+	if best < 0 {
+		return 0, nil, false
+	}
+
+	positions := make([]int, len(q))
+	for j := last; j >= 0; j-- {
+		positions[j] = at
+		at = from[j][at]
+	}
+
+	return best, positions, true
+}
+
+// wordStart returns whether the character at i starts a word, which is when
+// it follows a character which is not a letter or digit, or is an upper case
+// letter following a lower case one.
+func wordStart(t []rune, i int) bool {
+
+	if i == 0 {
+		return true
+	}
+
+	prev := t[i-1]
+
+	switch {
+	case !unicode.IsLetter(prev) && !unicode.IsDigit(prev):
+		return true
+	case unicode.IsLower(prev) && unicode.IsUpper(t[i]):
+		return true
+	}
+
+	return false
+}
+
+// highlightMatches returns text with the characters at the given positions
+// underlined.
+func highlightMatches(text string, positions []int) string {
+
+	if len(positions) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+
+	next := 0
+	on := false
+	for i, r := range []rune(text) {
+		match := next < len(positions) && positions[next] == i
+		if match {
+			next++
+		}
+
+		switch {
+		case match && !on:
+			b.WriteString("\033[4m")
+		case !match && on:
+			b.WriteString("\033[24m")
+		}
+		on = match
+
+		b.WriteRune(r)
+	}
+
+	if on {
+		b.WriteString("\033[24m")
+	}
+
+	return b.String()
+}
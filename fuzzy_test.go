@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+
+	cases := []struct {
+		name      string
+		query     string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{name: "empty query", query: "", text: "apple", ok: true},
+		{name: "prefix", query: "app", text: "apple", ok: true, positions: []int{0, 1, 2}},
+		{name: "ignores case", query: "APL", text: "apple", ok: true, positions: []int{0, 2, 3}},
+		{name: "in order", query: "ae", text: "apple", ok: true, positions: []int{0, 4}},
+		{name: "out of order", query: "ea", text: "apple", ok: false},
+		{name: "longer than text", query: "apples", text: "apple", ok: false},
+		{name: "missing character", query: "apx", text: "apple", ok: false},
+		{name: "prefers word starts", query: "nb", text: "nano banana", ok: true, positions: []int{0, 5}},
+		{name: "prefers consecutive", query: "ana", text: "xaxnxa banana", ok: true, positions: []int{8, 9, 10}},
+		{name: "camel case word start", query: "gb", text: "golangBuild", ok: true, positions: []int{0, 6}},
+		{name: "non-ASCII", query: "éc", text: "Éclair", ok: true, positions: []int{0, 1}},
+		{name: "positions in runes", query: "b", text: "日本b", ok: true, positions: []int{2}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, positions, ok := fuzzyMatch(c.query, c.text)
+			if ok != c.ok {
+				t.Fatalf("got ok %v; want %v", ok, c.ok)
+			}
+			if !slices.Equal(positions, c.positions) {
+				t.Errorf("got positions %v; want %v", positions, c.positions)
+			}
+		})
+	}
+}
+
+func TestFuzzyMatch_ranking(t *testing.T) {
+
+	cases := []struct {
+		query  string
+		better string
+		worse  string
+	}{
+		{query: "apl", better: "apple", worse: "maple"},
+		{query: "ban", better: "banana", worse: "bxaxn"},
+		{query: "fb", better: "foo bar", worse: "fabric"},
+		{query: "dev", better: "develop", worse: "dxexv"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			better, _, ok1 := fuzzyMatch(c.query, c.better)
+			worse, _, ok2 := fuzzyMatch(c.query, c.worse)
+			if !ok1 || !ok2 {
+				t.Fatal("expected both to match")
+			}
+			if better <= worse {
+				t.Errorf("got score %d for %q and %d for %q; want the first higher",
+					better, c.better, worse, c.worse)
+			}
+		})
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+
+	cases := []struct {
+		name      string
+		text      string
+		positions []int
+		want      string
+	}{
+		{name: "none", text: "apple", want: "apple"},
+		{name: "consecutive", text: "apple", positions: []int{0, 1}, want: "\033[4map\033[24mple"},
+		{name: "separate", text: "apple", positions: []int{0, 4}, want: "\033[4ma\033[24mppl\033[4me\033[24m"},
+		{name: "runes", text: "日本b", positions: []int{1}, want: "日\033[4m本\033[24mb"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := highlightMatches(c.text, c.positions); got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -4,10 +4,8 @@ go 1.22
 
 require (
 	github.com/ergochat/readline v0.1.2
+	golang.org/x/sys v0.20.0
 	golang.org/x/term v0.20.0
 )
 
-require (
-	golang.org/x/sys v0.20.0 // indirect
-	golang.org/x/text v0.9.0 // indirect
-)
+require golang.org/x/text v0.9.0
@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+)
+
+// guardian keeps track of what changed the state of a terminal, like raw mode,
+// a hidden cursor, the alternate screen or mouse reporting, so that it can be
+// restored when the program is interrupted or panics.
+type guardian struct {
+	mu        sync.Mutex
+	restorers []restorer
+	signals   chan os.Signal
+	done      chan struct{}
+
+	// ignoreSignals is set when the application handles the termination
+	// signals itself
+	ignoreSignals bool
+}
+
+// restorer changed the state of a terminal, which it restores when closed.
+// Closing must be possible more than once.
+type restorer interface {
+	close()
+}
+
+var guard = &guardian{}
+
+// terminationSignals are the signals on which terminals are restored before
+// the program terminates.
+var terminationSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}
+
+// Restore restores the state of all terminals changed by widgets which are being
+// rendered: raw mode is exited, the cursor is shown again, the alternate screen
+// is left and mouse reporting is disabled.
+//
+// This is done automatically when a widget panics, and when the process receives
+// SIGINT, SIGTERM or SIGHUP. In the latter case, the signal is sent again once the
+// terminal is restored so that the process terminates. Applications handling these
+// signals themselves, for example to shut down gracefully, receive them twice
+// unless they use HandleSignals(false) and call Restore from their handler.
+//
+// Applications should call Restore before using os.Exit while a widget is
+// rendered, for example from a callback, and can use RestoreOnPanic to cover
+// panics happening elsewhere.
+func Restore() {
+
+	guard.mu.Lock()
+	restorers := slices.Clone(guard.restorers)
+	guard.mu.Unlock()
+
+	slices.Reverse(restorers)
+	for _, r := range restorers {
+		r.close()
+	}
+}
+
+// RestoreOnPanic restores terminals like Restore when the program panics,
+// after which it continues panicking. It must be deferred directly:
+//
+//	func main() {
+//		defer console.RestoreOnPanic()
+//		...
+//	}
+func RestoreOnPanic() {
+
+	if r := recover(); r != nil {
+		Restore()
+		panic(r)
+	}
+}
+
+// goSafe runs f in a goroutine, restoring terminals when it panics, since
+// the terminal must not stay in raw mode when, for example, a callback
+// computing a preview panics.
+func goSafe(f func()) {
+
+	go func() {
+		defer RestoreOnPanic()
+		f()
+	}()
+}
+
+// HandleSignals sets whether the terminal is restored, and the process
+// terminated, when it receives SIGINT, SIGTERM or SIGHUP while a widget is
+// rendered, which is the default. Applications which handle these signals
+// themselves should disable this, and call Restore from their handler.
+func HandleSignals(enabled bool) {
+
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	guard.ignoreSignals = !enabled
+
+	switch {
+	case enabled && len(guard.restorers) > 0:
+		guard.notify()
+	case !enabled:
+		guard.stopNotify()
+	}
+}
+
+// add starts guarding r; signals are handled while anything is guarded.
+func (g *guardian) add(r restorer) {
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.restorers = append(g.restorers, r)
+
+	if !g.ignoreSignals {
+		g.notify()
+	}
+}
+
+// remove stops guarding r.
+func (g *guardian) remove(r restorer) {
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	i := slices.Index(g.restorers, r)
+	if i == -1 {
+		return
+	}
+
+	g.restorers = slices.Delete(g.restorers, i, i+1)
+
+	if len(g.restorers) == 0 {
+		g.stopNotify()
+	}
+}
+
+// notify starts handling the termination signals, unless already doing so.
+// The lock must be held.
+func (g *guardian) notify() {
+
+	if g.signals != nil {
+		return
+	}
+
+	g.signals = make(chan os.Signal, 1)
+	g.done = make(chan struct{})
+	signal.Notify(g.signals, terminationSignals...)
+	go g.handleSignals(g.signals, g.done)
+}
+
+// stopNotify stops handling the termination signals. The lock must be held.
+func (g *guardian) stopNotify() {
+
+	if g.signals == nil {
+		return
+	}
+
+	signal.Stop(g.signals)
+	close(g.done)
+	g.signals, g.done = nil, nil
+}
+
+func (g *guardian) handleSignals(signals chan os.Signal, done chan struct{}) {
+
+	select {
+	case sig := <-signals:
+		Restore()
+		// the signal is raised again, no longer relayed to us, so the program
+		// terminates like it would have without us catching it; handlers
+		// installed by the application are left alone
+		signal.Stop(signals)
+		raise(sig)
+	case <-done:
+	}
+}
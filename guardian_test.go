@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// rawTerminal is a Terminal keeping track of whether it is in raw mode.
+type rawTerminal struct {
+	Terminal
+	raw bool
+}
+
+func (rt *rawTerminal) MakeRaw() (func() error, error) {
+
+	rt.raw = true
+
+	return func() error {
+		rt.raw = false
+		return nil
+	}, nil
+}
+
+func newRawTerminal() *rawTerminal {
+
+	return &rawTerminal{Terminal: NewStreamTerminal(strings.NewReader(""), io.Discard, 80, 24)}
+}
+
+func TestRestore(t *testing.T) {
+
+	rt := newRawTerminal()
+	sn, err := newSession(rt, sessionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sn.close()
+
+	if !rt.raw {
+		t.Fatal("expected raw mode while the session is open")
+	}
+
+	Restore()
+	if rt.raw {
+		t.Error("expected raw mode exited after Restore")
+	}
+}
+
+func TestRestoreOnPanic(t *testing.T) {
+
+	rt := newRawTerminal()
+	sn, err := newSession(rt, sessionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sn.close()
+
+	defer func() {
+		if r := recover(); r != "failed" {
+			t.Errorf("got panic %v; want it to continue", r)
+		}
+		if rt.raw {
+			t.Error("expected raw mode exited before continuing to panic")
+		}
+	}()
+
+	func() {
+		defer RestoreOnPanic()
+		panic("failed")
+	}()
+}
@@ -0,0 +1,594 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// KeyCode identifies a key which was pressed. Printable characters are reported
+// using KeyRune.
+type KeyCode int
+
+const (
+	KeyUnknown KeyCode = iota
+	KeyRune
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyInsert
+	KeyDelete
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyMouse
+)
+
+var keyNames = map[KeyCode]string{
+	KeyUnknown:   "unknown",
+	KeyEnter:     "enter",
+	KeyTab:       "tab",
+	KeyBackspace: "backspace",
+	KeyEscape:    "esc",
+	KeyUp:        "up",
+	KeyDown:      "down",
+	KeyRight:     "right",
+	KeyLeft:      "left",
+	KeyHome:      "home",
+	KeyEnd:       "end",
+	KeyPageUp:    "pgup",
+	KeyPageDown:  "pgdown",
+	KeyInsert:    "insert",
+	KeyDelete:    "delete",
+	KeyMouse:     "mouse",
+}
+
+// KeyMod is a bit mask of the modifiers held while a key was pressed.
+type KeyMod int
+
+const (
+	ModShift KeyMod = 1 << iota
+	ModAlt
+	ModCtrl
+)
+
+// MouseButton identifies the mouse button of a MouseEvent.
+type MouseButton int
+
+const (
+	MouseLeft MouseButton = iota
+	MouseMiddle
+	MouseRight
+	MouseWheelUp
+	MouseWheelDown
+)
+
+// MouseEvent is a mouse button being pressed or released, or the wheel being
+// turned, as reported by the terminal when mouse reporting is enabled.
+type MouseEvent struct {
+	Button  MouseButton
+	Release bool
+	Motion  bool
+	// X and Y are the column and row, both starting at 1.
+	X int
+	Y int
+}
+
+// Key is a key press read from the terminal. When Code is KeyMouse, it is a
+// mouse event described by Mouse.
+type Key struct {
+	Code  KeyCode
+	Rune  rune
+	Mod   KeyMod
+	Mouse MouseEvent
+
+	// row and col are reported with keyCursorPosition
+	row int
+	col int
+	// report is the content of the Operating System Command reported
+	// with keyOSC
+	report string
+}
+
+// String returns a readable representation of k such as "ctrl+c" or "alt+up".
+func (k Key) String() string {
+
+	var b strings.Builder
+
+	if k.Mod&ModCtrl != 0 {
+		b.WriteString("ctrl+")
+	}
+	if k.Mod&ModAlt != 0 {
+		b.WriteString("alt+")
+	}
+	if k.Mod&ModShift != 0 {
+		b.WriteString("shift+")
+	}
+
+	switch {
+	case k.Code == KeyRune:
+		b.WriteRune(k.Rune)
+	case k.Code >= KeyF1 && k.Code <= KeyF12:
+		b.WriteString("f" + strconv.Itoa(int(k.Code-KeyF1)+1))
+	default:
+		b.WriteString(keyNames[k.Code])
+	}
+
+	return b.String()
+}
+
+// IsInterrupt returns whether k is Ctrl+C.
+func (k Key) IsInterrupt() bool {
+
+	return k.Code == KeyRune && k.Rune == 'c' && k.Mod == ModCtrl
+}
+
+// InputWaiter is implemented by a Terminal which can wait for input to become
+// available without consuming it.
+type InputWaiter interface {
+	// WaitInput waits at most timeout for input and returns whether there is any.
+	WaitInput(timeout time.Duration) (bool, error)
+}
+
+// DefaultEscapeTimeout is how long a KeyReader waits for the rest of an escape
+// sequence before reporting a lone Escape.
+const DefaultEscapeTimeout = 50 * time.Millisecond
+
+// KeyReader reads bytes from a terminal and decodes them into key presses,
+// including escape sequences for cursor, editing and function keys, and
+// multibyte UTF-8 characters.
+type KeyReader struct {
+	r   io.Reader
+	buf []byte
+
+	escapeTimeout time.Duration
+
+	// number of cursor position reports expected, which are otherwise
+	// indistinguishable from a modified F3
+	expectPositions int
+
+	// last is the input the last key read was decoded from
+	last []byte
+}
+
+// NewKeyReader returns a KeyReader decoding key presses read from r.
+//
+// When r implements InputWaiter, a lone Escape is told apart from the start of an
+// escape sequence by waiting for more input. Otherwise, an escape sequence is
+// expected to be available in one read.
+func NewKeyReader(r io.Reader) *KeyReader {
+
+	return &KeyReader{
+		r:             r,
+		escapeTimeout: DefaultEscapeTimeout,
+	}
+}
+
+// SetEscapeTimeout sets how long to wait for the rest of an escape sequence.
+func (kr *KeyReader) SetEscapeTimeout(d time.Duration) {
+
+	kr.escapeTimeout = d
+}
+
+// expectCursorPosition makes the reader report the response to a cursor
+// position query as keyCursorPosition.
+func (kr *KeyReader) expectCursorPosition() {
+
+	kr.expectPositions++
+}
+
+// Buffered returns whether input was read which was not yet decoded.
+func (kr *KeyReader) Buffered() bool {
+
+	return len(kr.buf) > 0
+}
+
+// ReadKey blocks until a key is pressed and returns it.
+func (kr *KeyReader) ReadKey() (Key, error) {
+
+	if len(kr.buf) == 0 {
+		if err := kr.fill(); err != nil {
+			return Key{}, err
+		}
+	}
+
+	for {
+		key, n, complete := decodeKey(kr.buf)
+		if complete {
+			kr.last, kr.buf = kr.buf[:n], kr.buf[n:]
+			return kr.cursorPosition(key), nil
+		}
+
+		more, err := kr.waitMore()
+		if err != nil {
+			return Key{}, err
+		}
+
+		if more {
+			err = kr.fill()
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, io.EOF) {
+				return Key{}, err
+			}
+		}
+
+		key, n = decodeIncompleteKey(kr.buf)
+		kr.last, kr.buf = kr.buf[:n], kr.buf[n:]
+		return key, nil
+	}
+}
+
+// cursorPosition turns key into a cursor position report when one is expected.
+func (kr *KeyReader) cursorPosition(key Key) Key {
+
+	if kr.expectPositions == 0 || key.Code != KeyF3 || key.row == 0 {
+		return key
+	}
+
+	kr.expectPositions--
+
+	return Key{Code: keyCursorPosition, row: key.row, col: key.col}
+}
+
+// waitMore waits for more input to complete a sequence, returning false when
+// nothing arrives in time or when r cannot tell.
+func (kr *KeyReader) waitMore() (bool, error) {
+
+	waiter, ok := kr.r.(InputWaiter)
+	if !ok {
+		return false, nil
+	}
+
+	return waiter.WaitInput(kr.escapeTimeout)
+}
+
+func (kr *KeyReader) fill() error {
+
+	p := make([]byte, 64)
+
+	n, err := kr.r.Read(p)
+	kr.buf = append(kr.buf, p[:n]...)
+
+	if n > 0 {
+		return nil
+	}
+
+	if err == nil {
+		err = io.ErrNoProgress
+	}
+
+	return fmt.Errorf("reading input (%w)", err)
+}
+
+// decodeKey decodes the key at the start of b, returning it together with
+// the number of bytes used. When b does not hold a complete key, complete
+// is false.
+func decodeKey(b []byte) (key Key, n int, complete bool) {
+
+	if len(b) == 0 {
+		return Key{}, 0, false
+	}
+
+	switch c := b[0]; {
+	case c == 0x1b:
+		return decodeEscape(b)
+	case c == '\r' || c == '\n':
+		return Key{Code: KeyEnter}, 1, true
+	case c == '\t':
+		return Key{Code: KeyTab}, 1, true
+	case c == 0x7f || c == 0x08:
+		return Key{Code: KeyBackspace}, 1, true
+	case c == 0x00:
+		return Key{Code: KeyRune, Rune: ' ', Mod: ModCtrl}, 1, true
+	case c < 0x1b:
+		return Key{Code: KeyRune, Rune: rune('a' + c - 1), Mod: ModCtrl}, 1, true
+	case c < 0x20:
+		return Key{Code: KeyRune, Rune: rune('\\' + c - 0x1c), Mod: ModCtrl}, 1, true
+	case c < utf8.RuneSelf:
+		return Key{Code: KeyRune, Rune: rune(c)}, 1, true
+	}
+
+	if !utf8.FullRune(b) {
+		return Key{}, 0, false
+	}
+
+	r, size := utf8.DecodeRune(b)
+	if r == utf8.RuneError {
+		return Key{Code: KeyUnknown}, size, true
+	}
+
+	return Key{Code: KeyRune, Rune: r}, size, true
+}
+
+// decodeIncompleteKey decodes what is left in b when no more input arrived.
+func decodeIncompleteKey(b []byte) (Key, int) {
+
+	if b[0] != 0x1b {
+		// truncated UTF-8
+		return Key{Code: KeyUnknown}, len(b)
+	}
+
+	if len(b) == 1 || b[1] == 0x1b {
+		return Key{Code: KeyEscape}, 1
+	}
+
+	if len(b) == 2 {
+		// Alt combined with '[' or 'O'
+		return Key{Code: KeyRune, Rune: rune(b[1]), Mod: ModAlt}, 2
+	}
+
+	return Key{Code: KeyUnknown}, len(b)
+}
+
+func decodeEscape(b []byte) (Key, int, bool) {
+
+	if len(b) == 1 {
+		return Key{}, 0, false
+	}
+
+	switch b[1] {
+	case '[':
+		return decodeCSI(b)
+	case ']':
+		return decodeOSC(b)
+	case 'O':
+		return decodeSS3(b)
+	case 0x1b:
+		// some terminals send Alt combined with a sequence as ESC ESC [ A
+		if len(b) == 2 {
+			return Key{}, 0, false
+		}
+		if b[2] != '[' && b[2] != 'O' {
+			return Key{Code: KeyEscape}, 1, true
+		}
+		key, n, complete := decodeEscape(b[1:])
+		if !complete {
+			return Key{}, 0, false
+		}
+		key.Mod |= ModAlt
+		return key, n + 1, true
+	}
+
+	key, n, complete := decodeKey(b[1:])
+	if !complete {
+		return Key{}, 0, false
+	}
+	key.Mod |= ModAlt
+
+	return key, n + 1, true
+}
+
+var csiFinalKeys = map[byte]KeyCode{
+	'A': KeyUp,
+	'B': KeyDown,
+	'C': KeyRight,
+	'D': KeyLeft,
+	'H': KeyHome,
+	'F': KeyEnd,
+	'P': KeyF1,
+	'Q': KeyF2,
+	'R': KeyF3,
+	'S': KeyF4,
+	'Z': KeyTab, // Shift+Tab
+}
+
+var csiTildeKeys = map[int]KeyCode{
+	1:  KeyHome,
+	2:  KeyInsert,
+	3:  KeyDelete,
+	4:  KeyEnd,
+	5:  KeyPageUp,
+	6:  KeyPageDown,
+	7:  KeyHome,
+	8:  KeyEnd,
+	11: KeyF1,
+	12: KeyF2,
+	13: KeyF3,
+	14: KeyF4,
+	15: KeyF5,
+	17: KeyF6,
+	18: KeyF7,
+	19: KeyF8,
+	20: KeyF9,
+	21: KeyF10,
+	23: KeyF11,
+	24: KeyF12,
+}
+
+// decodeCSI decodes a Control Sequence Introducer sequence such as ESC [ 1 ; 5 A.
+func decodeCSI(b []byte) (Key, int, bool) {
+
+	// parameter and intermediate bytes are followed by a final byte
+	end := -1
+	for i := 2; i < len(b); i++ {
+		if b[i] >= 0x40 && b[i] <= 0x7e {
+			end = i
+			break
+		}
+		if b[i] < 0x20 || b[i] > 0x3f {
+			// not a valid sequence; report what we have as unknown
+			return Key{Code: KeyUnknown}, i, true
+		}
+	}
+
+	if end == -1 {
+		return Key{}, 0, false
+	}
+
+	if b[2] == '<' && (b[end] == 'M' || b[end] == 'm') {
+		return decodeMouse(b[3:end], b[end] == 'm'), end + 1, true
+	}
+
+	if b[2] == '?' && b[end] == 'c' {
+		// response to a device attributes query
+		return Key{Code: keyDeviceAttributes}, end + 1, true
+	}
+
+	params := csiParams(b[2:end])
+	final := b[end]
+	n := end + 1
+
+	var code KeyCode
+	if final == '~' {
+		if len(params) > 0 {
+			code = csiTildeKeys[params[0]]
+		}
+	} else {
+		code = csiFinalKeys[final]
+	}
+
+	if code == KeyUnknown {
+		return Key{Code: KeyUnknown}, n, true
+	}
+
+	key := Key{Code: code}
+	if final == 'Z' {
+		key.Mod = ModShift
+	}
+
+	if len(params) > 1 {
+		key.Mod |= modifierParam(params[1])
+	}
+
+	if final == 'R' && len(params) == 2 {
+		// could be the response to a cursor position query
+		key.row, key.col = params[0], params[1]
+	}
+
+	return key, n, true
+}
+
+// decodeOSC decodes an Operating System Command, which terminals send in
+// response to queries such as ESC ] 11 ; ? ESC \ for the background color. It
+// ends with BEL or ESC \.
+func decodeOSC(b []byte) (Key, int, bool) {
+
+	for i := 2; i < len(b); i++ {
+		switch {
+		case b[i] == 0x07:
+			return Key{Code: keyOSC, report: string(b[2:i])}, i + 1, true
+		case b[i] == 0x1b && i+1 < len(b) && b[i+1] == '\\':
+			return Key{Code: keyOSC, report: string(b[2:i])}, i + 2, true
+		case b[i] == 0x1b && i+1 < len(b):
+			// not terminated; report what we have as unknown
+			return Key{Code: KeyUnknown}, i, true
+		}
+	}
+
+	return Key{}, 0, false
+}
+
+// decodeMouse decodes the parameters of an SGR mouse report such as
+// ESC [ < 0 ; 10 ; 5 M, where the final byte is 'm' when released.
+func decodeMouse(b []byte, release bool) Key {
+
+	params := csiParams(b)
+	if len(params) != 3 {
+		return Key{Code: KeyUnknown}
+	}
+
+	cb := params[0]
+	event := MouseEvent{
+		Release: release,
+		Motion:  cb&32 != 0,
+		X:       params[1],
+		Y:       params[2],
+	}
+
+	switch {
+	case cb&64 != 0 && cb&1 == 0:
+		event.Button = MouseWheelUp
+	case cb&64 != 0:
+		event.Button = MouseWheelDown
+	default:
+		event.Button = MouseButton(cb & 3)
+	}
+
+	var mod KeyMod
+	if cb&4 != 0 {
+		mod |= ModShift
+	}
+	if cb&8 != 0 {
+		mod |= ModAlt
+	}
+	if cb&16 != 0 {
+		mod |= ModCtrl
+	}
+
+	return Key{Code: KeyMouse, Mod: mod, Mouse: event}
+}
+
+// decodeSS3 decodes a Single Shift 3 sequence such as ESC O A.
+func decodeSS3(b []byte) (Key, int, bool) {
+
+	if len(b) < 3 {
+		return Key{}, 0, false
+	}
+
+	if b[2] == 'M' {
+		return Key{Code: KeyEnter}, 3, true
+	}
+
+	code, ok := csiFinalKeys[b[2]]
+	if !ok || b[2] == 'Z' {
+		return Key{Code: KeyUnknown}, 3, true
+	}
+
+	return Key{Code: code}, 3, true
+}
+
+// csiParams parses the semicolon separated numeric parameters of a sequence.
+// Missing or non-numeric parameters are reported as 0.
+func csiParams(b []byte) []int {
+
+	if len(b) == 0 {
+		return nil
+	}
+
+	fields := strings.Split(string(b), ";")
+	params := make([]int, len(fields))
+	for i, f := range fields {
+		params[i], _ = strconv.Atoi(f)
+	}
+
+	return params
+}
+
+// modifierParam converts the xterm modifier parameter, which is 1 plus a bit
+// mask of Shift, Alt and Ctrl.
+func modifierParam(p int) KeyMod {
+
+	if p < 2 {
+		return 0
+	}
+
+	return KeyMod(p-1) & (ModShift | ModAlt | ModCtrl)
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeKey(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		input    string
+		want     Key
+		n        int
+		complete bool
+	}{
+		{name: "empty", input: "", n: 0, complete: false},
+		{name: "rune", input: "a", want: Key{Code: KeyRune, Rune: 'a'}, n: 1, complete: true},
+		{name: "only first rune", input: "ab", want: Key{Code: KeyRune, Rune: 'a'}, n: 1, complete: true},
+		{name: "multibyte rune", input: "é", want: Key{Code: KeyRune, Rune: 'é'}, n: 2, complete: true},
+		{name: "truncated multibyte rune", input: "\xc3", n: 0, complete: false},
+		{name: "invalid UTF-8", input: "\xff", want: Key{Code: KeyUnknown}, n: 1, complete: true},
+		{name: "carriage return", input: "\r", want: Key{Code: KeyEnter}, n: 1, complete: true},
+		{name: "line feed", input: "\n", want: Key{Code: KeyEnter}, n: 1, complete: true},
+		{name: "tab", input: "\t", want: Key{Code: KeyTab}, n: 1, complete: true},
+		{name: "delete", input: "\x7f", want: Key{Code: KeyBackspace}, n: 1, complete: true},
+		{name: "backspace", input: "\x08", want: Key{Code: KeyBackspace}, n: 1, complete: true},
+		{name: "ctrl+space", input: "\x00", want: Key{Code: KeyRune, Rune: ' ', Mod: ModCtrl}, n: 1, complete: true},
+		{name: "ctrl+c", input: "\x03", want: Key{Code: KeyRune, Rune: 'c', Mod: ModCtrl}, n: 1, complete: true},
+		{name: "ctrl+backslash", input: "\x1c", want: Key{Code: KeyRune, Rune: '\\', Mod: ModCtrl}, n: 1, complete: true},
+		{name: "lone escape", input: "\x1b", n: 0, complete: false},
+		{name: "alt+x", input: "\x1bx", want: Key{Code: KeyRune, Rune: 'x', Mod: ModAlt}, n: 2, complete: true},
+		{name: "escape escape x", input: "\x1b\x1bx", want: Key{Code: KeyEscape}, n: 1, complete: true},
+		{name: "alt+up using double escape", input: "\x1b\x1b[A", want: Key{Code: KeyUp, Mod: ModAlt}, n: 4, complete: true},
+		{name: "ss3 up", input: "\x1bOA", want: Key{Code: KeyUp}, n: 3, complete: true},
+		{name: "ss3 keypad enter", input: "\x1bOM", want: Key{Code: KeyEnter}, n: 3, complete: true},
+		{name: "ss3 F1", input: "\x1bOP", want: Key{Code: KeyF1}, n: 3, complete: true},
+		{name: "ss3 incomplete", input: "\x1bO", n: 0, complete: false},
+		{name: "ss3 unknown", input: "\x1bOz", want: Key{Code: KeyUnknown}, n: 3, complete: true},
+		{name: "osc terminated by BEL", input: "\x1b]11;rgb:0000/0000/0000\x07",
+			want: Key{Code: keyOSC, report: "11;rgb:0000/0000/0000"}, n: 24, complete: true},
+		{name: "osc terminated by ST", input: "\x1b]11;x\x1b\\",
+			want: Key{Code: keyOSC, report: "11;x"}, n: 8, complete: true},
+		{name: "osc incomplete", input: "\x1b]11;x", n: 0, complete: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			key, n, complete := decodeKey([]byte(c.input))
+			if complete != c.complete {
+				t.Fatalf("got complete %v; want %v", complete, c.complete)
+			}
+			if n != c.n {
+				t.Errorf("got %d bytes; want %d", n, c.n)
+			}
+			if complete && key != c.want {
+				t.Errorf("got %+v; want %+v", key, c.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCSI(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		input    string
+		want     Key
+		complete bool
+	}{
+		{name: "up", input: "\x1b[A", want: Key{Code: KeyUp}, complete: true},
+		{name: "down", input: "\x1b[B", want: Key{Code: KeyDown}, complete: true},
+		{name: "home", input: "\x1b[H", want: Key{Code: KeyHome}, complete: true},
+		{name: "end", input: "\x1b[F", want: Key{Code: KeyEnd}, complete: true},
+		{name: "shift+tab", input: "\x1b[Z", want: Key{Code: KeyTab, Mod: ModShift}, complete: true},
+		{name: "shift+down", input: "\x1b[1;2B", want: Key{Code: KeyDown, Mod: ModShift}, complete: true},
+		{name: "ctrl+up", input: "\x1b[1;5A", want: Key{Code: KeyUp, Mod: ModCtrl}, complete: true},
+		{name: "ctrl+alt+shift+right", input: "\x1b[1;8C", want: Key{Code: KeyRight, Mod: ModShift | ModAlt | ModCtrl}, complete: true},
+		{name: "page up", input: "\x1b[5~", want: Key{Code: KeyPageUp}, complete: true},
+		{name: "shift+page down", input: "\x1b[6;2~", want: Key{Code: KeyPageDown, Mod: ModShift}, complete: true},
+		{name: "delete", input: "\x1b[3~", want: Key{Code: KeyDelete}, complete: true},
+		{name: "rxvt home", input: "\x1b[7~", want: Key{Code: KeyHome}, complete: true},
+		{name: "F5", input: "\x1b[15~", want: Key{Code: KeyF5}, complete: true},
+		{name: "F12", input: "\x1b[24~", want: Key{Code: KeyF12}, complete: true},
+		{name: "unknown tilde", input: "\x1b[99~", want: Key{Code: KeyUnknown}, complete: true},
+		{name: "tilde without parameters", input: "\x1b[~", want: Key{Code: KeyUnknown}, complete: true},
+		{name: "unknown final", input: "\x1b[y", want: Key{Code: KeyUnknown}, complete: true},
+		{name: "F3 or cursor position", input: "\x1b[12;40R", want: Key{Code: KeyF3, Mod: ModShift | ModAlt | ModCtrl, row: 12, col: 40}, complete: true},
+		{name: "device attributes", input: "\x1b[?62;22c", want: Key{Code: keyDeviceAttributes}, complete: true},
+		{name: "mouse press", input: "\x1b[<0;10;5M",
+			want: Key{Code: KeyMouse, Mouse: MouseEvent{Button: MouseLeft, X: 10, Y: 5}}, complete: true},
+		{name: "mouse release", input: "\x1b[<0;10;5m",
+			want: Key{Code: KeyMouse, Mouse: MouseEvent{Button: MouseLeft, Release: true, X: 10, Y: 5}}, complete: true},
+		{name: "mouse right with ctrl", input: "\x1b[<18;1;1M",
+			want: Key{Code: KeyMouse, Mod: ModCtrl, Mouse: MouseEvent{Button: MouseRight, X: 1, Y: 1}}, complete: true},
+		{name: "mouse motion", input: "\x1b[<32;3;4M",
+			want: Key{Code: KeyMouse, Mouse: MouseEvent{Button: MouseLeft, Motion: true, X: 3, Y: 4}}, complete: true},
+		{name: "wheel up", input: "\x1b[<64;3;4M",
+			want: Key{Code: KeyMouse, Mouse: MouseEvent{Button: MouseWheelUp, X: 3, Y: 4}}, complete: true},
+		{name: "wheel down", input: "\x1b[<65;3;4M",
+			want: Key{Code: KeyMouse, Mouse: MouseEvent{Button: MouseWheelDown, X: 3, Y: 4}}, complete: true},
+		{name: "mouse missing parameter", input: "\x1b[<0;10M", want: Key{Code: KeyUnknown}, complete: true},
+		{name: "incomplete", input: "\x1b[1;5", complete: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			key, n, complete := decodeCSI([]byte(c.input))
+			if complete != c.complete {
+				t.Fatalf("got complete %v; want %v", complete, c.complete)
+			}
+			if !complete {
+				return
+			}
+			if n != len(c.input) {
+				t.Errorf("got %d bytes; want %d", n, len(c.input))
+			}
+			if key != c.want {
+				t.Errorf("got %+v; want %+v", key, c.want)
+			}
+		})
+	}
+
+	t.Run("invalid byte ends sequence", func(t *testing.T) {
+		key, n, complete := decodeCSI([]byte("\x1b[1\x01A"))
+		if !complete || n != 3 || key.Code != KeyUnknown {
+			t.Errorf("got %+v, %d, %v; want unknown key of 3 bytes", key, n, complete)
+		}
+	})
+}
+
+func TestKeyReader(t *testing.T) {
+
+	t.Run("sequences in one read", func(t *testing.T) {
+		kr := NewKeyReader(strings.NewReader("a\x1b[Bé\r"))
+
+		want := []Key{
+			{Code: KeyRune, Rune: 'a'},
+			{Code: KeyDown},
+			{Code: KeyRune, Rune: 'é'},
+			{Code: KeyEnter},
+		}
+
+		for _, w := range want {
+			key, err := kr.ReadKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if key != w {
+				t.Errorf("got %+v; want %+v", key, w)
+			}
+		}
+
+		if _, err := kr.ReadKey(); err == nil {
+			t.Error("expected error at end of input")
+		}
+	})
+
+	t.Run("lone escape at end of input", func(t *testing.T) {
+		kr := NewKeyReader(strings.NewReader("\x1b"))
+
+		key, err := kr.ReadKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.Code != KeyEscape {
+			t.Errorf("got %+v; want Escape", key)
+		}
+	})
+
+	t.Run("cursor position when expected", func(t *testing.T) {
+		kr := NewKeyReader(strings.NewReader("\x1b[3;7R\x1b[3;7R"))
+		kr.expectCursorPosition()
+
+		key, err := kr.ReadKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.Code != keyCursorPosition || key.row != 3 || key.col != 7 {
+			t.Errorf("got %+v; want cursor position 3;7", key)
+		}
+
+		key, err = kr.ReadKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.Code != KeyF3 {
+			t.Errorf("got %+v; want F3 when no position is expected", key)
+		}
+	})
+}
+
+func TestKey_String(t *testing.T) {
+
+	cases := []struct {
+		key  Key
+		want string
+	}{
+		{key: Key{Code: KeyRune, Rune: 'c', Mod: ModCtrl}, want: "ctrl+c"},
+		{key: Key{Code: KeyUp, Mod: ModAlt}, want: "alt+up"},
+		{key: Key{Code: KeyTab, Mod: ModShift}, want: "shift+tab"},
+		{key: Key{Code: KeyF11}, want: "f11"},
+		{key: Key{Code: KeyPageDown, Mod: ModCtrl | ModShift}, want: "ctrl+shift+pgdown"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.want, func(t *testing.T) {
+			if got := c.key.String(); got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,656 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// list holds what widgets showing a scrollable list of options, like
+// Selection, have in common.
+type list struct {
+	options []string
+
+	wantShowing int
+	showing     int
+	pointer     int
+	start       int
+	end         int
+
+	// how options wider than the terminal are shown, using at most available
+	// columns and maxLines lines for the options; lines is the number of
+	// lines shown
+	overflow  Overflow
+	ellipsis  string
+	available int
+	maxLines  int
+	lines     int
+
+	// mark returns what is shown in front of option p, like a checkbox, and
+	// markWidth is the widest it can be; mark is nil when nothing is shown
+	mark      func(p int) string
+	markWidth int
+
+	// message is shown below the options when not empty
+	message string
+
+	// filterable is set when typing filters the options; query is what was
+	// typed, rows are the options shown, which are those matching the query,
+	// and matches the positions of the matched characters of each row
+	filterable bool
+	query      string
+	rows       []int
+	matches    [][]int
+
+	// infos holds what is shown besides the label of each option, and is nil
+	// when there is nothing; descriptions is where descriptions are shown
+	infos        []optionInfo
+	descriptions DescriptionPlacement
+
+	// styled is set when the terminal can show styles, for example to
+	// underline matched characters or dim disabled options, and unicode
+	// when it can show characters outside ASCII
+	styled  bool
+	unicode bool
+
+	// source provides the rows when the options are not known up front, in
+	// which case options, rows and infos are not used
+	source rowSource
+
+	// preview shows the option with the pointer, and is nil when not used;
+	// width is the number of columns used by the options
+	preview          *preview
+	previewPlacement PreviewPlacement
+	width            int
+
+	// groups is the group of each option, and headings the heading of each
+	// group; groups is nil when the options are not grouped
+	groups   []int
+	headings []string
+
+	// row on the screen of the first option shown, starting at 1; 0 when
+	// not known
+	origin int
+	clicks clicks
+}
+
+// SetOverflow sets how options which are wider than the terminal are shown.
+// By default, they are cut off using an ellipsis.
+func (l *list) SetOverflow(o Overflow) {
+
+	l.overflow = o
+}
+
+// SetDescriptionPlacement sets where the descriptions of options are shown.
+// By default, they are shown after the labels.
+func (l *list) SetDescriptionPlacement(d DescriptionPlacement) {
+
+	l.descriptions = d
+}
+
+// SetPreviewPlacement sets where the preview of the option with the pointer
+// is shown. By default, it is shown next to the options when the terminal is
+// wide enough, and below them otherwise.
+func (l *list) SetPreviewPlacement(p PreviewPlacement) {
+
+	l.previewPlacement = p
+}
+
+// SetShowing sets the number of options to be shown in the list.
+// If n is less than 1, it sets the number of options to the terminal height minus 3.
+// Otherwise, it sets the number of options to n.
+// The height of the terminal is retrieved when the list is rendered.
+func (l *list) SetShowing(n int) {
+
+	l.wantShowing = n
+}
+
+// updateLayout sets the number of options shown and the space available to
+// them based on the size of the terminal, which can change while rendering.
+func (l *list) updateLayout(t Terminal, theme SelectionTheme) {
+
+	width, height := terminalSize(t)
+
+	// lines available to the options
+	lines := height - 3
+	if l.preview != nil {
+		width, lines = l.preview.layout(l.previewPlacement, width, lines)
+	}
+	l.width = width
+
+	// space taken by the theme around the option, and the leading space
+	overhead := max(Width(fmt.Sprintf(theme.Selected, "")),
+		Width(fmt.Sprintf(theme.Unselected, ""))) + 1 + l.markWidth
+
+	l.available = max(1, width-1-overhead)
+	l.maxLines = max(1, lines)
+
+	if l.wantShowing < 1 || l.wantShowing > lines {
+		l.showing = max(1, lines)
+	} else {
+		l.showing = l.wantShowing
+	}
+
+	if l.source == nil && len(l.options) < l.showing {
+		l.showing = len(l.options)
+	}
+}
+
+// run shows the options and handles the keys moving the pointer, filtering,
+// resizing and suspending, until handle, which gets all other keys, returns
+// true or an error. Escape clears the query, or aborts with ErrAborted when
+// there is none, like Ctrl+C.
+func (l *list) run(t Terminal, sn *session, fr *frame, theme SelectionTheme,
+	handle func(key Key) (bool, error)) error {
+
+	if l.source != nil {
+		l.source.start(sn.wake)
+		defer l.source.stop()
+	}
+
+	// outside run, the pointer is at an option instead of a row
+	l.query, l.rows = "", nil
+	l.filter()
+	defer func() {
+		if l.source == nil && l.pointer < len(l.rows) {
+			l.pointer = l.rows[l.pointer]
+		}
+	}()
+
+	if l.preview != nil {
+		l.preview.start(sn.wake)
+		defer l.preview.stop()
+	}
+
+	l.updateLayout(t, theme)
+	l.moveTo(l.pointer)
+	l.renderOptions(fr, theme)
+	l.locate(sn)
+
+	for {
+		key, err := sn.readKey()
+		if err != nil {
+			return err
+		}
+
+		if l.source != nil {
+			if err := l.source.err(); err != nil {
+				return err
+			}
+		}
+
+		if n, ok := l.previewScroll(key); ok {
+			l.preview.scroll(n)
+			l.renderOptions(fr, theme)
+			continue
+		}
+
+		pointer := l.pointer
+
+		switch key.Code {
+		case KeyUp:
+			pointer--
+		case KeyDown:
+			pointer++
+		case KeyPageUp:
+			pointer -= l.showing
+		case KeyPageDown:
+			pointer += l.showing
+		case KeyHome:
+			pointer = 0
+		case KeyEnd:
+			pointer = l.rowCount() - 1
+		case keyResize:
+			l.updateLayout(t, theme)
+			fr.invalidate()
+		case keySuspend:
+			// clear the options and draw them again when continued
+			fr.clear()
+			if err := sn.suspendProcess(); err != nil {
+				return err
+			}
+			l.updateLayout(t, theme)
+		case keyCursorPosition:
+			// the cursor is on the line below the options
+			l.origin = key.row - l.lines
+			continue
+		case keyWake:
+			// the preview was computed, or options were fetched
+		case KeyMouse:
+			switch m := key.Mouse; {
+			case m.Button == MouseWheelUp:
+				pointer--
+			case m.Button == MouseWheelDown:
+				pointer++
+			default:
+				done, err := handle(key)
+				if err != nil || done {
+					return err
+				}
+				pointer = l.pointer
+			}
+		case KeyEscape:
+			if l.query == "" {
+				return ErrAborted
+			}
+			l.query = ""
+			l.filter()
+			pointer = l.pointer
+		case KeyBackspace:
+			if l.query == "" {
+				continue
+			}
+			_, size := utf8.DecodeLastRuneInString(l.query)
+			l.query = l.query[:len(l.query)-size]
+			l.filter()
+			pointer = l.pointer
+		default:
+			if key.IsInterrupt() {
+				return ErrAborted
+			}
+			if l.filterable && key.Code == KeyRune && key.Mod&(ModCtrl|ModAlt) == 0 {
+				l.query += string(key.Rune)
+				l.filter()
+				pointer = l.pointer
+				break
+			}
+			done, err := handle(key)
+			if err != nil || done {
+				return err
+			}
+			pointer = l.pointer
+		}
+
+		l.moveTo(pointer)
+		l.renderOptions(fr, theme)
+
+		if key.Code == keyResize || key.Code == keySuspend {
+			l.locate(sn)
+		}
+	}
+}
+
+// previewScroll returns the number of lines the preview is scrolled by key,
+// which is Shift with Up, Down, PageUp or PageDown, or false when key does not
+// scroll the preview.
+func (l *list) previewScroll(key Key) (int, bool) {
+
+	if l.preview == nil || key.Mod != ModShift {
+		return 0, false
+	}
+
+	switch key.Code {
+	case KeyUp:
+		return -1, true
+	case KeyDown:
+		return 1, true
+	case KeyPageUp:
+		return -l.preview.height, true
+	case KeyPageDown:
+		return l.preview.height, true
+	}
+
+	return 0, false
+}
+
+// inOptions returns whether the options are shown at screen column col,
+// instead of the preview.
+func (l *list) inOptions(col int) bool {
+
+	return l.preview == nil || !l.preview.side || col <= l.width
+}
+
+// filter shows the options matching the query. When there is a query, the
+// pointer is moved to the best match; otherwise it stays on the same option.
+func (l *list) filter() {
+
+	if l.source != nil {
+		l.source.setQuery(l.query)
+		l.start, l.pointer = 0, 0
+		if l.preview != nil {
+			// rows are other options now
+			l.preview.stop()
+		}
+		return
+	}
+
+	current := l.pointer
+	if l.pointer < len(l.rows) {
+		current = l.rows[l.pointer]
+	}
+
+	l.rows, l.matches = l.rows[:0], l.matches[:0]
+	l.start, l.pointer = 0, 0
+
+	best := -1
+	for i, option := range l.options {
+		score, positions, ok := fuzzyMatch(l.query, StripANSI(option))
+		if !ok {
+			continue
+		}
+
+		switch {
+		case l.query == "" && i == current:
+			l.pointer = len(l.rows)
+		case l.query != "" && score > best && !l.disabled(i):
+			best = score
+			l.pointer = len(l.rows)
+		}
+
+		l.rows = append(l.rows, i)
+		l.matches = append(l.matches, positions)
+	}
+}
+
+// locate queries where the options are shown on the screen, which is only
+// needed when using the mouse.
+func (l *list) locate(sn *session) {
+
+	l.origin = 0
+
+	if sn.opts.mouse {
+		sn.queryCursor()
+	}
+}
+
+// optionAt returns the option shown on the given screen row.
+func (l *list) optionAt(row int) (int, bool) {
+
+	if l.origin == 0 {
+		return 0, false
+	}
+
+	line := row - l.origin - l.headerLines()
+	if line < 0 {
+		return 0, false
+	}
+
+	for p := l.start; p < l.end; p++ {
+		if _, ok := l.heading(p); ok {
+			line--
+		}
+		if line < 0 {
+			return 0, false
+		}
+
+		n := len(l.optionLines(p))
+		if line < n {
+			return p, true
+		}
+		line -= n
+	}
+
+	return 0, false
+}
+
+// moveTo moves the pointer to option p, scrolling the visible options
+// so that the pointer stays in view.
+func (l *list) moveTo(p int) {
+
+	lenOpts := l.rowCount()
+
+	p = max(0, min(p, lenOpts-1))
+	if p < l.pointer {
+		l.pointer = l.enabledRow(p, -1)
+	} else {
+		l.pointer = l.enabledRow(p, 1)
+	}
+
+	if lenOpts == 0 {
+		l.start, l.end = 0, 0
+		return
+	}
+
+	if l.pointer < l.start {
+		l.start = l.pointer
+	}
+	l.start = min(l.start, lenOpts-1)
+
+	// show as many options from start as fit; wrapped options and headings
+	// take more than one line
+	l.end = l.start + 1
+	for l.end < min(l.start+l.showing, lenOpts) {
+		l.end++
+		if l.countLines() > l.maxLines {
+			l.end--
+			break
+		}
+	}
+
+	if l.pointer >= l.end {
+		l.start, l.end = l.pointer, l.pointer+1
+	}
+
+	// fill what is left of the screen with the options before start, which
+	// is the case after scrolling down, or at the end of the options
+	for l.start > 0 && l.end-l.start < l.showing {
+		l.start--
+		if l.countLines() > l.maxLines {
+			l.start++
+			break
+		}
+	}
+}
+
+// enabledRow returns the first row, starting at p and going in direction dir,
+// of which the option is not disabled. When there is none, the other direction
+// is tried; when all are disabled, p is returned.
+func (l *list) enabledRow(p, dir int) int {
+
+	for _, d := range []int{dir, -dir} {
+		for r := p; r >= 0 && r < l.rowCount(); r += d {
+			if !l.rowDisabled(r) {
+				return r
+			}
+		}
+	}
+
+	return p
+}
+
+// disabled returns whether option i is disabled.
+func (l *list) disabled(i int) bool {
+
+	return l.infos != nil && l.infos[i].disabled
+}
+
+// rowDisabled returns whether the option in row p is disabled, which is not
+// known while it is being fetched.
+func (l *list) rowDisabled(p int) bool {
+
+	_, info, ok := l.row(p)
+	return ok && info.disabled
+}
+
+// rowCount returns the number of rows.
+func (l *list) rowCount() int {
+
+	if l.source != nil {
+		return l.source.count()
+	}
+
+	return len(l.rows)
+}
+
+// row returns the label of the option in row p and what is shown besides it,
+// or false when the option is still being fetched.
+func (l *list) row(p int) (string, optionInfo, bool) {
+
+	if l.source != nil {
+		return l.source.row(p)
+	}
+
+	var info optionInfo
+	if l.infos != nil {
+		info = l.infos[l.rows[p]]
+	}
+
+	return l.options[l.rows[p]], info, true
+}
+
+// rowMatches returns the positions of the characters of label, the label of
+// the option in row p, matching the query.
+func (l *list) rowMatches(p int, label string) []int {
+
+	if l.source == nil {
+		return l.matches[p]
+	}
+
+	// the source filtered the options, possibly matching differently
+	_, positions, _ := fuzzyMatch(l.query, label)
+	return positions
+}
+
+// optionLines returns the lines showing the option in row p.
+func (l *list) optionLines(p int) []string {
+
+	option, info, ok := l.row(p)
+	if !ok {
+		return []string{dim(l.ellipsis, l.styled)}
+	}
+
+	if l.styled && l.query != "" && StripANSI(option) == option {
+		option = highlightMatches(option, l.rowMatches(p, option))
+	}
+
+	option = info.decorate(option, l.descriptions == DescriptionInline, l.styled)
+	lines := l.overflow.fit(option, l.available, l.maxLines, l.ellipsis)
+
+	if l.descriptions == DescriptionFocused && p == l.pointer && info.description != "" {
+		// the description is wrapped under the label, using at most a few lines
+		description := dim(info.description, l.styled)
+		lines = append(lines, OverflowWrap.fit(description, l.available, min(3, l.maxLines), l.ellipsis)...)
+	}
+
+	return lines
+}
+
+// optionIndex returns the option in row p, which is the row itself when the
+// rows come from a source.
+func (l *list) optionIndex(p int) int {
+
+	if l.source != nil {
+		return p
+	}
+
+	return l.rows[p]
+}
+
+// heading returns the heading shown above the option in row p, which is
+// when it is the first row of its group, or the first row shown.
+func (l *list) heading(p int) (string, bool) {
+
+	if l.groups == nil {
+		return "", false
+	}
+
+	g := l.groups[l.rows[p]]
+	if l.headings[g] == "" || (p > l.start && l.groups[l.rows[p-1]] == g) {
+		return "", false
+	}
+
+	return l.headings[g], true
+}
+
+// headerLines returns the number of lines shown above the options.
+func (l *list) headerLines() int {
+
+	if l.query == "" {
+		return 0
+	}
+
+	return 1
+}
+
+// countLines returns the number of lines taken by the options shown,
+// including their headings.
+func (l *list) countLines() int {
+
+	var n int
+	for p := l.start; p < l.end; p++ {
+		if _, ok := l.heading(p); ok {
+			n++
+		}
+		n += len(l.optionLines(p))
+	}
+
+	return n
+}
+
+func (l *list) renderOptions(fr *frame, theme SelectionTheme) {
+
+	if l.source != nil {
+		// fetch what is shown, and what is shown after paging down
+		l.source.fetch(l.start, l.end+l.showing)
+	}
+
+	var lines []string
+
+	if l.query != "" {
+		header := "Filter: " + l.query
+		if l.rowCount() == 0 && (l.source == nil || !l.source.loading()) {
+			header += " (no matches)"
+		}
+		lines = append(lines, Truncate(" "+header, l.width-1, l.ellipsis))
+	}
+
+	headingFormat := theme.Heading
+	if headingFormat == "" {
+		headingFormat = "-- %s --"
+	}
+
+	for i := l.start; i < l.end; i++ {
+
+		if heading, ok := l.heading(i); ok {
+			lines = append(lines, " "+fmt.Sprintf(headingFormat, Truncate(heading, l.available, l.ellipsis)))
+		}
+
+		format := theme.Unselected
+		if i == l.pointer {
+			format = theme.Selected
+		}
+
+		// lines after the first are indented by the width of what the
+		// theme shows in front of the option
+		prefix, suffix, _ := strings.Cut(format, "%s")
+		if l.mark != nil {
+			prefix += l.mark(l.rows[i])
+		}
+
+		for n, line := range l.optionLines(i) {
+			if n > 0 {
+				prefix = blankText(prefix)
+			}
+			lines = append(lines, " "+prefix+line+suffix)
+		}
+	}
+
+	if l.source != nil && l.source.loading() {
+		lines = append(lines, Truncate(" "+dim("Loading"+l.ellipsis, l.styled), l.width-1, l.ellipsis))
+	}
+
+	if l.message != "" {
+		lines = append(lines, Truncate(" "+l.message, l.width-1, l.ellipsis))
+	}
+
+	if l.preview != nil {
+		var loaded bool
+		if l.pointer < l.rowCount() {
+			_, _, loaded = l.row(l.pointer)
+		}
+
+		if loaded {
+			l.preview.show(l.optionIndex(l.pointer))
+		} else {
+			l.preview.stop()
+		}
+		lines = l.preview.compose(lines, l.width, l.styled, l.unicode, l.ellipsis)
+	}
+
+	fr.render(lines)
+	l.lines = len(lines)
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import "time"
+
+// doubleClickInterval is the maximum time between the clicks of a double-click.
+const doubleClickInterval = 400 * time.Millisecond
+
+// clicks keeps track of clicks on items, like options, to detect double-clicks.
+type clicks struct {
+	last  time.Time
+	index int
+}
+
+// click records a click on the item at index and returns whether it
+// completed a double-click.
+func (c *clicks) click(index int) bool {
+
+	now := time.Now()
+	double := index == c.index && now.Sub(c.last) <= doubleClickInterval
+
+	c.index, c.last = index, now
+	if double {
+		// a third click starts over
+		c.last = time.Time{}
+	}
+
+	return double
+}
+
+// isClick returns whether m is the left button being pressed.
+func (m MouseEvent) isClick() bool {
+
+	return m.Button == MouseLeft && !m.Release && !m.Motion
+}
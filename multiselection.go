@@ -0,0 +1,356 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func NewMultiSelection[V ~[]E, E any](options []string, values V) (*MultiSelection[E], error) {
+
+	if len(options) != len(values) {
+		return nil, fmt.Errorf("number of options and values does not match")
+	}
+
+	s := &MultiSelection[E]{
+		list:    list{options: options},
+		values:  values,
+		checked: make([]bool, len(options)),
+	}
+
+	s.SetTheme(defaultThemeName())
+	s.SetShowing(len(options))
+
+	return s, nil
+}
+
+// MultiSelection represents a list of options with corresponding values of
+// which any number can be checked. A user can use the Up- and Down-cursor keys
+// to move between options, Space to check or uncheck an option, `a` to check
+// all and `n` to check none, and push Enter to confirm.
+type MultiSelection[E any] struct {
+	widget
+	list
+
+	values  []E
+	checked []bool
+
+	// minimum and maximum number of options checked; maxChecked is 0 when
+	// there is no maximum
+	minChecked int
+	maxChecked int
+
+	selectedValues  []E
+	selectedOptions []string
+
+	theme Theme
+}
+
+// SetTheme sets the theme used when rendering. When the terminal cannot show
+// the theme, for example because it needs Unicode or colors, a theme which can
+// be shown is used instead. Unknown themes are ignored.
+func (s *MultiSelection[E]) SetTheme(t Theme) {
+
+	if _, ok := themeSpec(t); ok {
+		s.theme = t
+	}
+}
+
+// SetMin sets the minimum number of options which must be checked.
+func (s *MultiSelection[E]) SetMin(n int) {
+
+	s.minChecked = max(0, n)
+}
+
+// SetMax sets the maximum number of options which can be checked. When n is
+// less than 1, there is no maximum.
+func (s *MultiSelection[E]) SetMax(n int) {
+
+	s.maxChecked = max(0, n)
+}
+
+// SetChecked checks the options at the given positions, unchecking all others.
+// Positions outside the options are ignored.
+func (s *MultiSelection[E]) SetChecked(p ...int) {
+
+	clear(s.checked)
+
+	for _, i := range p {
+		if i >= 0 && i < len(s.checked) {
+			s.checked[i] = true
+		}
+	}
+}
+
+// SetCheckedFunc checks the options for whose values f returns true,
+// unchecking all others.
+func (s *MultiSelection[E]) SetCheckedFunc(f func(E) bool) {
+
+	for i, v := range s.values {
+		s.checked[i] = f(v)
+	}
+}
+
+// Selected returns the values of the checked options, in the order of the options.
+func (s *MultiSelection[E]) Selected() []E {
+
+	return s.selectedValues
+}
+
+// SelectedOptions returns the checked options, in the order of the options.
+func (s *MultiSelection[E]) SelectedOptions() []string {
+
+	return s.selectedOptions
+}
+
+// RenderWithTheme renders the MultiSelection with the specified theme. If the
+// theme with the given name does not exist, the default theme is used.
+func (s *MultiSelection[E]) RenderWithTheme(themeName Theme) error {
+
+	if _, ok := themeSpec(themeName); !ok {
+		themeName = s.theme
+	}
+
+	return s.render(themeName)
+}
+
+// Render renders the MultiSelection.
+func (s *MultiSelection[E]) Render() error {
+	return s.render(s.theme)
+}
+
+func (s *MultiSelection[E]) render(themeName Theme) error {
+
+	if s.pointer < 0 || s.pointer >= len(s.options) {
+		s.pointer = 0
+	}
+
+	t := s.getTerminal()
+
+	if ok, err := s.interactive(t); err != nil {
+		return err
+	} else if !ok {
+		return s.renderLines(t)
+	}
+
+	sn, err := newSession(t, s.sessionOptions())
+	if err != nil {
+		return err
+	}
+	fr := &frame{w: t}
+	defer func() {
+		fr.clear()
+		sn.close()
+	}()
+
+	caps := DetectCapabilities(t)
+	spec := resolveTheme(sn.themeVariant(themeName), caps)
+	theme := spec.Selection
+	s.ellipsis = ellipsis(caps)
+
+	checked, unchecked := checkMarks(theme)
+	s.markWidth = max(Width(checked), Width(unchecked))
+	s.mark = func(p int) string {
+		if s.checked[p] {
+			return checked
+		}
+		return unchecked
+	}
+	defer func() { s.mark, s.markWidth, s.message = nil, 0, "" }()
+
+	return s.run(t, sn, fr, theme, func(key Key) (bool, error) {
+
+		s.message = ""
+
+		var msg string
+
+		switch key.Code {
+		case KeyEnter:
+			if msg = s.checkCount(); msg == "" {
+				s.choose()
+				return true, nil
+			}
+		case KeyRune:
+			switch key.Rune {
+			case ' ':
+				if s.pointer < len(s.rows) {
+					msg = s.toggle(s.rows[s.pointer])
+				}
+			case 'a':
+				msg = s.setAll(true)
+			case 'n':
+				msg = s.setAll(false)
+			}
+		case KeyMouse:
+			if !key.Mouse.isClick() {
+				break
+			}
+			if p, ok := s.optionAt(key.Mouse.Y); ok {
+				s.pointer = p
+				msg = s.toggle(s.rows[p])
+			}
+		}
+
+		if msg != "" {
+			s.message = spec.Form.Error.RenderFor(caps, msg)
+		}
+
+		return false, nil
+	})
+}
+
+// checkMarks returns what theme shows in front of checked and unchecked options.
+func checkMarks(theme SelectionTheme) (string, string) {
+
+	checked, unchecked := theme.Checked, theme.Unchecked
+	if checked == "" {
+		checked = "[x] "
+	}
+	if unchecked == "" {
+		unchecked = "[ ] "
+	}
+
+	return checked, unchecked
+}
+
+// toggle checks or unchecks the option at position p. Checking is refused
+// when more options than the maximum would be checked, returning why.
+func (s *MultiSelection[E]) toggle(p int) string {
+
+	if !s.checked[p] && s.maxChecked > 0 && s.countChecked() >= s.maxChecked {
+		return s.maxMessage()
+	}
+
+	s.checked[p] = !s.checked[p]
+
+	return ""
+}
+
+// setAll checks or unchecks all options. Checking is refused when more
+// options than the maximum would be checked, returning why.
+func (s *MultiSelection[E]) setAll(checked bool) string {
+
+	if checked && s.maxChecked > 0 && len(s.checked) > s.maxChecked {
+		return s.maxMessage()
+	}
+
+	for i := range s.checked {
+		s.checked[i] = checked
+	}
+
+	return ""
+}
+
+func (s *MultiSelection[E]) countChecked() int {
+
+	var n int
+	for _, c := range s.checked {
+		if c {
+			n++
+		}
+	}
+
+	return n
+}
+
+// checkCount returns why the number of options checked is not allowed, or an
+// empty string when it is.
+func (s *MultiSelection[E]) checkCount() string {
+
+	n := s.countChecked()
+
+	switch {
+	case n < s.minChecked:
+		return fmt.Sprintf("Select at least %d %s", s.minChecked, plural(s.minChecked, "option"))
+	case s.maxChecked > 0 && n > s.maxChecked:
+		return s.maxMessage()
+	}
+
+	return ""
+}
+
+func (s *MultiSelection[E]) maxMessage() string {
+
+	return fmt.Sprintf("Select at most %d %s", s.maxChecked, plural(s.maxChecked, "option"))
+}
+
+func plural(n int, word string) string {
+
+	if n == 1 {
+		return word
+	}
+
+	return word + "s"
+}
+
+// renderLines renders the MultiSelection as a numbered list, reading the
+// numbers of the options to check, separated by commas, as a line of input.
+func (s *MultiSelection[E]) renderLines(t Terminal) error {
+
+	digits := len(strconv.Itoa(len(s.options)))
+
+	var current []string
+	for i, option := range s.options {
+		fmt.Fprintf(t, "%*d) %s\n", digits+1, i+1, option)
+		if s.checked[i] {
+			current = append(current, strconv.Itoa(i+1))
+		}
+	}
+
+	prompt := fmt.Sprintf("Enter numbers separated by commas [%s]: ", strings.Join(current, ","))
+
+	_, err := promptLine(t, prompt, func(answer string) (int, string) {
+		if answer == "" {
+			if msg := s.checkCount(); msg != "" {
+				return -1, msg
+			}
+			return 0, ""
+		}
+
+		checked := make([]bool, len(s.options))
+		for _, field := range strings.Split(answer, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return -1, fmt.Sprintf("Invalid number %q", strings.TrimSpace(field))
+			}
+			if n < 1 || n > len(s.options) {
+				return -1, fmt.Sprintf("Number must be between 1 and %d", len(s.options))
+			}
+			checked[n-1] = true
+		}
+
+		previous := s.checked
+		s.checked = checked
+		if msg := s.checkCount(); msg != "" {
+			s.checked = previous
+			return -1, msg
+		}
+
+		return 0, ""
+	})
+	if err != nil {
+		return err
+	}
+
+	s.choose()
+
+	return nil
+}
+
+// choose selects the checked options.
+func (s *MultiSelection[E]) choose() {
+
+	s.selectedValues = []E{}
+	s.selectedOptions = []string{}
+
+	for i, c := range s.checked {
+		if c {
+			s.selectedValues = append(s.selectedValues, s.values[i])
+			s.selectedOptions = append(s.selectedOptions, s.options[i])
+		}
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/golistic/console"
+	"github.com/golistic/console/consoletest"
+)
+
+func newFruitMultiSelection(t *testing.T, term *consoletest.Terminal) *console.MultiSelection[int] {
+
+	t.Helper()
+
+	s, err := console.NewMultiSelection(fruits, []int{1, 2, 3, 4, 5, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetTerminal(term)
+
+	return s
+}
+
+func TestMultiSelection_keys(t *testing.T) {
+
+	cases := []struct {
+		name string
+		max  int
+		keys []string
+		want []int
+	}{
+		{name: "none", keys: []string{consoletest.Enter}, want: []int{}},
+		{name: "space", keys: []string{consoletest.Space, consoletest.Down, consoletest.Down, consoletest.Space, consoletest.Enter}, want: []int{1, 3}},
+		{name: "uncheck", keys: []string{consoletest.Space, consoletest.Space, consoletest.Enter}, want: []int{}},
+		{name: "all", keys: []string{"a", consoletest.Enter}, want: []int{1, 2, 3, 4, 5, 6}},
+		{name: "all then none", keys: []string{"a", "n", consoletest.Enter}, want: []int{}},
+		{name: "max refuses space", max: 1, keys: []string{consoletest.Space, consoletest.Down, consoletest.Space, consoletest.Enter}, want: []int{1}},
+		{name: "max refuses all", max: 3, keys: []string{"a", consoletest.Enter}, want: []int{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(30, 10)
+			term.Type(c.keys...)
+
+			s := newFruitMultiSelection(t, term)
+			s.SetMax(c.max)
+			if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := s.Selected(); !slices.Equal(got, c.want) {
+				t.Errorf("got %v; want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestMultiSelection_maxMessage(t *testing.T) {
+
+	term := consoletest.NewTerminal(30, 10)
+	term.Type("a")
+	term.Do(func() {
+		if got := term.Screen().String(); !strings.Contains(got, "Select at most 3 options") {
+			t.Errorf("expected message about the maximum; got:\n%s", got)
+		}
+		if got := term.Screen().String(); strings.Contains(got, "[x]") {
+			t.Errorf("expected no options checked; got:\n%s", got)
+		}
+	})
+	term.Type(consoletest.Enter)
+
+	s := newFruitMultiSelection(t, term)
+	s.SetMax(3)
+	if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMultiSelection_SetCheckedFunc(t *testing.T) {
+
+	term := consoletest.NewTerminal(30, 10)
+	term.Type(consoletest.Enter)
+
+	s := newFruitMultiSelection(t, term)
+	s.SetCheckedFunc(func(v int) bool { return v%2 == 0 })
+	if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := s.Selected(), []int{2, 4, 6}; !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestMultiSelection_noOptions(t *testing.T) {
+
+	term := consoletest.NewTerminal(30, 10)
+	term.Type(consoletest.Space, consoletest.Enter)
+
+	s, err := console.NewMultiSelection([]string{}, []int{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetTerminal(term)
+
+	if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.Selected(); len(got) != 0 {
+		t.Errorf("got %v; want none", got)
+	}
+}
+
+func TestMultiSelection_SetChecked(t *testing.T) {
+
+	term := consoletest.NewTerminal(30, 10)
+	term.Type(consoletest.Space, consoletest.Enter)
+
+	s := newFruitMultiSelection(t, term)
+	s.SetChecked(0, 2, 10)
+	if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+		t.Fatal(err)
+	}
+
+	// the pointer starts on the first option, which space unchecks
+	if got, want := s.Selected(), []int{3}; !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
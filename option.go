@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+// Option is an option which can be chosen, together with the value it stands
+// for. Description and Hint are shown dimmed after the label. Disabled options
+// are shown dimmed, with the reason when given, and cannot be chosen.
+type Option[E any] struct {
+	Label       string
+	Value       E
+	Description string
+	Hint        string
+
+	Disabled       bool
+	DisabledReason string
+}
+
+// DescriptionPlacement is where the descriptions of options are shown.
+type DescriptionPlacement int
+
+const (
+	// DescriptionInline shows descriptions after the labels of all options.
+	DescriptionInline DescriptionPlacement = iota
+	// DescriptionFocused shows only the description of the option with the
+	// pointer, under its label.
+	DescriptionFocused
+)
+
+// optionInfo is what is shown of an Option besides its label.
+type optionInfo struct {
+	description string
+	hint        string
+	disabled    bool
+	reason      string
+}
+
+// splitOptions returns the labels, values and other information of options.
+func splitOptions[E any](options []Option[E]) ([]string, []E, []optionInfo) {
+
+	labels := make([]string, len(options))
+	values := make([]E, len(options))
+	infos := make([]optionInfo, len(options))
+
+	for i, o := range options {
+		labels[i] = o.Label
+		values[i] = o.Value
+		infos[i] = o.info()
+	}
+
+	return labels, values, infos
+}
+
+// info returns what is shown of o besides its label.
+func (o Option[E]) info() optionInfo {
+
+	return optionInfo{
+		description: o.Description,
+		hint:        o.Hint,
+		disabled:    o.Disabled,
+		reason:      o.DisabledReason,
+	}
+}
+
+// dim returns text shown dimmed when styled is set. Dimming is turned off
+// using the sequence for normal intensity, keeping other styles active.
+func dim(text string, styled bool) string {
+
+	if !styled || text == "" {
+		return text
+	}
+
+	return "\033[2m" + text + "\033[22m"
+}
+
+// decorate returns label followed by the hint, the reason the option is
+// disabled and, when inline is set, the description.
+func (info optionInfo) decorate(label string, inline, styled bool) string {
+
+	var extra string
+
+	if info.hint != "" {
+		extra += " " + info.hint
+	}
+
+	switch {
+	case info.disabled && info.reason != "":
+		extra += " (" + info.reason + ")"
+	case info.disabled && !styled:
+		// without dimming, disabled options are not recognizable otherwise
+		extra += " (disabled)"
+	}
+
+	if inline && info.description != "" {
+		extra += " - " + info.description
+	}
+
+	if info.disabled {
+		return dim(label+extra, styled)
+	}
+
+	return label + dim(extra, styled)
+}
+
+// refusal returns the message shown when the disabled option with the given
+// label is chosen.
+func (info optionInfo) refusal(label string) string {
+
+	if info.reason == "" {
+		return label + " cannot be chosen"
+	}
+
+	return label + " cannot be chosen: " + info.reason
+}
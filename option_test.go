@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console_test
+
+import (
+	"testing"
+
+	"github.com/golistic/console"
+	"github.com/golistic/console/consoletest"
+)
+
+// letters are options of which the first, fourth and last are disabled.
+var letters = []console.Option[string]{
+	{Label: "alpha", Value: "a", Disabled: true, DisabledReason: "retired"},
+	{Label: "bravo", Value: "b"},
+	{Label: "charlie", Value: "c"},
+	{Label: "delta", Value: "d", Disabled: true},
+	{Label: "echo", Value: "e"},
+	{Label: "foxtrot", Value: "f", Disabled: true},
+}
+
+func newLetterSelection(t *testing.T, term *consoletest.Terminal) *console.Selection[string] {
+
+	t.Helper()
+
+	s, err := console.NewSelectionFromOptions(letters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetTerminal(term)
+
+	return s
+}
+
+func TestSelection_disabledKeys(t *testing.T) {
+
+	cases := []struct {
+		name string
+		keys []string
+		want string
+	}{
+		{name: "first skipped", keys: []string{consoletest.Enter}, want: "b"},
+		{name: "down skips", keys: []string{consoletest.Down, consoletest.Down, consoletest.Enter}, want: "e"},
+		{name: "up skips", keys: []string{consoletest.End, consoletest.Up, consoletest.Enter}, want: "c"},
+		{name: "up at first enabled", keys: []string{consoletest.Up, consoletest.Enter}, want: "b"},
+		{name: "down at last enabled", keys: []string{consoletest.End, consoletest.Down, consoletest.Enter}, want: "e"},
+		{name: "home", keys: []string{consoletest.End, consoletest.Home, consoletest.Enter}, want: "b"},
+		{name: "end", keys: []string{consoletest.End, consoletest.Enter}, want: "e"},
+		{name: "page down", keys: []string{consoletest.PageDown, consoletest.Enter}, want: "e"},
+		{name: "page up", keys: []string{consoletest.End, consoletest.PageUp, consoletest.Enter}, want: "b"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(40, 10)
+			term.Type(c.keys...)
+
+			s := newLetterSelection(t, term)
+			if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := s.Selected(); got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSelection_disabledLines(t *testing.T) {
+
+	term := consoletest.NewTerminal(40, 20)
+	term.SetIsTerminal(false)
+	term.TypeText("1\n3\n")
+
+	s := newLetterSelection(t, term)
+	if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.Selected(); got != "c" {
+		t.Errorf("got %q; want %q", got, "c")
+	}
+
+	want := ` 1) alpha (retired)
+ 2) bravo
+ 3) charlie
+ 4) delta (disabled)
+ 5) echo
+ 6) foxtrot (disabled)
+Enter number [2]: 1
+alpha cannot be chosen: retired
+Enter number [2]: 3`
+	if got := term.Screen().String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSelection_descriptions(t *testing.T) {
+
+	options := []console.Option[int]{
+		{Label: "Postgres", Value: 1, Description: "relational", Hint: "recommended"},
+		{Label: "Redis", Value: 2, Description: "key-value"},
+	}
+
+	cases := []struct {
+		name      string
+		placement console.DescriptionPlacement
+		keys      []string
+		want      string
+	}{
+		{
+			name:      "inline",
+			placement: console.DescriptionInline,
+			want:      " > Postgres recommended - relational\n    Redis - key-value",
+		},
+		{
+			name:      "focused",
+			placement: console.DescriptionFocused,
+			want:      " > Postgres recommended\n   relational\n    Redis",
+		},
+		{
+			name:      "focused moves",
+			placement: console.DescriptionFocused,
+			keys:      []string{consoletest.Down},
+			want:      "    Postgres recommended\n > Redis\n   key-value",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(50, 10)
+			term.Type(c.keys...)
+			term.Do(func() {
+				if got := term.Screen().String(); got != c.want {
+					t.Errorf("got:\n%s\nwant:\n%s", got, c.want)
+				}
+			})
+			term.Type(consoletest.Enter)
+
+			s, err := console.NewSelectionFromOptions(options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.SetTerminal(term)
+			s.SetDescriptionPlacement(c.placement)
+
+			if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func newDisabledToggle(t *testing.T, term *consoletest.Terminal) *console.Toggle[bool] {
+
+	t.Helper()
+
+	tg, err := console.NewToggleFromOptions("Deploy?", []console.Option[bool]{
+		{Label: "Yes", Value: true, Disabled: true, DisabledReason: "frozen"},
+		{Label: "No", Value: false},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg.SetTerminal(term)
+
+	return tg
+}
+
+func TestToggle_disabled(t *testing.T) {
+
+	t.Run("keys", func(t *testing.T) {
+		term := consoletest.NewTerminal(40, 5)
+		term.Type(consoletest.Left)
+		term.Do(func() {
+			if got, want := term.Screen().String(), "Deploy?   Yes (frozen)  > No"; got != want {
+				t.Errorf("got %q; want %q", got, want)
+			}
+		})
+		term.Type(consoletest.Enter)
+
+		tg := newDisabledToggle(t, term)
+		tg.SetSelected(true)
+		if err := tg.RenderWithTheme(console.ThemeAscii); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := tg.Selected(); got != false {
+			t.Errorf("got %v; want false", got)
+		}
+	})
+
+	t.Run("lines", func(t *testing.T) {
+		term := consoletest.NewTerminal(40, 5)
+		term.SetIsTerminal(false)
+		term.TypeText("yes\n\n")
+
+		tg := newDisabledToggle(t, term)
+		if err := tg.RenderWithTheme(console.ThemeAscii); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := tg.Selected(); got != false {
+			t.Errorf("got %v; want false", got)
+		}
+
+		want := "Deploy? (Yes/No) [No]: yes\nYes cannot be chosen: frozen\nDeploy? (Yes/No) [No]:"
+		if got := term.Screen().String(); got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+}
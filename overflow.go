@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+// Overflow is how text which does not fit the width of the terminal is shown.
+type Overflow int
+
+const (
+	// OverflowEllipsis cuts the end of the text, replacing it with an ellipsis.
+	OverflowEllipsis Overflow = iota
+	// OverflowMiddle cuts the middle of the text, replacing it with an ellipsis,
+	// so that both start and end stay visible. This suits paths.
+	OverflowMiddle
+	// OverflowWrap breaks the text over multiple lines.
+	OverflowWrap
+)
+
+// fit returns the lines showing text within width columns, using at most
+// maxLines lines when wrapping.
+func (o Overflow) fit(text string, width, maxLines int, ellipsis string) []string {
+
+	switch o {
+	case OverflowMiddle:
+		return []string{TruncateMiddle(text, width, ellipsis)}
+	case OverflowWrap:
+		lines := Wrap(text, width)
+		if len(lines) > maxLines {
+			lines = lines[:max(1, maxLines)]
+			last := len(lines) - 1
+			lines[last] = Truncate(lines[last]+" "+ellipsis, width, ellipsis)
+		}
+		return lines
+	default:
+		return []string{Truncate(text, width, ellipsis)}
+	}
+}
+
+// ellipsis returns the ellipsis which can be shown with the capabilities.
+func ellipsis(caps Capabilities) string {
+
+	if caps.Unicode {
+		return "…"
+	}
+
+	return "..."
+}
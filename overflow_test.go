@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golistic/console"
+	"github.com/golistic/console/consoletest"
+)
+
+// paths are options wider than the virtual screens they are shown on.
+var paths = []string{
+	"/usr/share/applications/firefox.desktop",
+	"/etc/ssh/sshd_config",
+	"/home/user/projects/console/README.md",
+	"/var/log/nginx/access.log",
+	"/opt/homebrew/Cellar/go/1.22.0/bin/gofmt",
+}
+
+func newPathSelection(t *testing.T, term *consoletest.Terminal, overflow console.Overflow) *console.Selection[int] {
+
+	t.Helper()
+
+	s, err := console.NewSelection(paths, []int{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetTerminal(term)
+	s.SetOverflow(overflow)
+
+	return s
+}
+
+func TestSelection_overflow(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		overflow console.Overflow
+		height   int
+		keys     []string
+	}{
+		{name: "overflow_ellipsis", overflow: console.OverflowEllipsis, height: 10},
+		{name: "overflow_middle", overflow: console.OverflowMiddle, height: 10},
+		{name: "overflow_wrap", overflow: console.OverflowWrap, height: 14},
+		{name: "overflow_wrap_scrolled", overflow: console.OverflowWrap, height: 8, keys: []string{consoletest.Down, consoletest.Down}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(24, c.height)
+			term.Type(c.keys...)
+			term.Do(func() {
+				consoletest.Golden(t, c.name, term.Screen().String())
+			})
+			term.Type(consoletest.Escape)
+
+			s := newPathSelection(t, term, c.overflow)
+			if err := s.RenderWithTheme(console.ThemeAscii); !errors.Is(err, console.ErrAborted) {
+				t.Fatalf("got error %v; want ErrAborted", err)
+			}
+		})
+	}
+}
+
+func TestSelection_overflowScrolling(t *testing.T) {
+
+	overflows := []struct {
+		name     string
+		overflow console.Overflow
+	}{
+		{name: "ellipsis", overflow: console.OverflowEllipsis},
+		{name: "middle", overflow: console.OverflowMiddle},
+		{name: "wrap", overflow: console.OverflowWrap},
+	}
+
+	for _, o := range overflows {
+		t.Run(o.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(24, 7)
+
+			// the pointer stays in view going down and back up
+			var pointers []int
+			for p := 1; p < len(paths); p++ {
+				pointers = append(pointers, p)
+			}
+			for p := len(paths) - 2; p >= 0; p-- {
+				pointers = append(pointers, p)
+			}
+
+			for i, p := range pointers {
+				key := consoletest.Down
+				if i >= len(paths)-1 {
+					key = consoletest.Up
+				}
+
+				term.Type(key)
+				term.Do(func() {
+					screen := term.Screen().String()
+					want := " > " + paths[p][:8]
+					if !strings.Contains(screen, want) {
+						t.Errorf("after key %d, want %q shown; got:\n%s", i+1, want, screen)
+					}
+				})
+			}
+			term.Type(consoletest.End, consoletest.Enter)
+
+			s := newPathSelection(t, term, o.overflow)
+			if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := s.Selected(); got != len(paths) {
+				t.Errorf("got %d; want %d", got, len(paths))
+			}
+		})
+	}
+}
+
+func TestToggle_clickCutOff(t *testing.T) {
+
+	term := consoletest.NewTerminal(20, 5)
+	term.Do(func() {
+		if got, want := term.Screen().String(), "Continue? > Yes   …"; got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+	})
+	term.Type(consoletest.Click(19, 1), consoletest.Enter)
+
+	tg := newYesNoToggle(t, term)
+	tg.SetMouse(true)
+	if err := tg.RenderWithTheme(console.ThemeAscii); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := tg.Selected(); got != true {
+		t.Errorf("got %v; want true, clicking the ellipsis does nothing", got)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// PreviewPlacement is where the preview of the option with the pointer is shown.
+type PreviewPlacement int
+
+const (
+	// PreviewAuto shows the preview next to the options when the terminal is
+	// at least 80 columns wide, and below them otherwise.
+	PreviewAuto PreviewPlacement = iota
+	// PreviewSide shows the preview next to the options.
+	PreviewSide
+	// PreviewBottom shows the preview below the options.
+	PreviewBottom
+)
+
+// previewSideMinWidth is the width of the terminal from which PreviewAuto
+// shows the preview next to the options.
+const previewSideMinWidth = 80
+
+// preview computes the text previewing an option in the background, and
+// shows it next to or below the options.
+type preview struct {
+	compute func(ctx context.Context, option int) string
+
+	// wake is called when the preview of the current option is computed
+	wake func()
+
+	mu      sync.Mutex
+	option  int
+	text    []string
+	ready   bool
+	cancel  context.CancelFunc
+	scrolls int
+
+	// layout: whether the pane is next to the options, and its size
+	side   bool
+	width  int
+	height int
+}
+
+// layout sets where the pane is shown and its size, and returns the width
+// and height left for the options.
+func (pv *preview) layout(placement PreviewPlacement, width, height int) (int, int) {
+
+	pv.side = placement == PreviewSide || (placement == PreviewAuto && width >= previewSideMinWidth)
+
+	if pv.side {
+		// the options take half, and the border 3 columns
+		left := width / 2
+		pv.width = max(1, width-left-3)
+		pv.height = max(1, height)
+		return left, height
+	}
+
+	// a third of the lines, and one for the border
+	pv.width = max(1, width-2)
+	pv.height = max(1, height/3)
+
+	return width, max(1, height-pv.height-1)
+}
+
+// start sets the function called when a preview is computed.
+func (pv *preview) start(wake func()) {
+
+	pv.mu.Lock()
+	defer pv.mu.Unlock()
+
+	pv.wake = wake
+}
+
+// show starts computing the preview of option, unless it is the option
+// already shown. The computation of the previous option is cancelled.
+func (pv *preview) show(option int) {
+
+	pv.mu.Lock()
+	defer pv.mu.Unlock()
+
+	if pv.cancel != nil && pv.option == option {
+		return
+	}
+
+	pv.stopLocked()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pv.option, pv.cancel = option, cancel
+	pv.text, pv.ready, pv.scrolls = nil, false, 0
+
+	goSafe(func() {
+		text := pv.compute(ctx, option)
+
+		pv.mu.Lock()
+		current := ctx.Err() == nil && pv.option == option
+		if current {
+			pv.text = strings.Split(strings.TrimRight(text, "\n"), "\n")
+			pv.ready = true
+		}
+		wake := pv.wake
+		pv.mu.Unlock()
+
+		if current && wake != nil {
+			wake()
+		}
+	})
+}
+
+// stop cancels computing the preview, and forgets what was shown.
+func (pv *preview) stop() {
+
+	pv.mu.Lock()
+	defer pv.mu.Unlock()
+
+	pv.stopLocked()
+}
+
+func (pv *preview) stopLocked() {
+
+	if pv.cancel != nil {
+		pv.cancel()
+		pv.cancel = nil
+	}
+	pv.text, pv.ready = nil, false
+}
+
+// scroll scrolls the text by n lines, which is negative to scroll up.
+func (pv *preview) scroll(n int) {
+
+	pv.mu.Lock()
+	defer pv.mu.Unlock()
+
+	pv.scrolls = max(0, min(pv.scrolls+n, len(pv.text)-pv.height))
+}
+
+// lines returns the lines of the pane, which are height lines at most.
+func (pv *preview) lines(loading, ellipsis string) []string {
+
+	pv.mu.Lock()
+	defer pv.mu.Unlock()
+
+	if pv.cancel == nil {
+		return nil
+	}
+
+	if !pv.ready {
+		return []string{loading}
+	}
+
+	end := min(pv.scrolls+pv.height, len(pv.text))
+
+	lines := make([]string, 0, end-pv.scrolls)
+	for _, line := range pv.text[pv.scrolls:end] {
+		lines = append(lines, Truncate(line, pv.width, ellipsis))
+	}
+
+	return lines
+}
+
+// compose returns the lines showing the options together with the pane.
+func (pv *preview) compose(options []string, optionsWidth int, styled, unicode bool, ellipsis string) []string {
+
+	border, rule := " | ", "-"
+	if unicode {
+		border, rule = " │ ", "─"
+	}
+
+	pane := pv.lines(dim("Loading"+ellipsis, styled), ellipsis)
+
+	if !pv.side {
+		// the pane keeps its height, so the options do not move
+		lines := append(options, " "+dim(strings.Repeat(rule, pv.width), styled))
+		for i := range pv.height {
+			var line string
+			if i < len(pane) {
+				line = pane[i]
+			}
+			lines = append(lines, " "+line)
+		}
+		return lines
+	}
+
+	lines := make([]string, max(len(options), len(pane)))
+	for i := range lines {
+		var left, right string
+		if i < len(options) {
+			left = options[i]
+		}
+		if i < len(pane) {
+			right = pane[i]
+		}
+		lines[i] = Pad(left, optionsWidth) + dim(border, styled) + right
+	}
+
+	return lines
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// panicChildEnv is set when the test runs as child process, in which a
+// callback panics while a widget is rendered.
+const panicChildEnv = "CONSOLE_TEST_PANIC_CHILD"
+
+// printingRestorer reports on standard output when it is closed.
+type printingRestorer struct{}
+
+func (printingRestorer) close() {
+
+	fmt.Println("restored")
+	guard.remove(printingRestorer{})
+}
+
+// testPanicRestores runs the test again as child process, in which render
+// renders a widget on a terminal without input, which must panic with a
+// message containing want after the terminal was restored.
+func testPanicRestores(t *testing.T, want string, render func(t Terminal)) {
+
+	t.Helper()
+
+	if os.Getenv(panicChildEnv) != "" {
+		guard.add(printingRestorer{})
+
+		// no keys are typed, so only the panic ends rendering
+		r, _ := io.Pipe()
+		render(NewStreamTerminal(r, io.Discard, 80, 24))
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$")
+	cmd.Env = append(os.Environ(), panicChildEnv+"=1")
+	out, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Fatal("expected the child process to fail")
+	}
+	if !strings.Contains(string(out), "restored") || !strings.Contains(string(out), want) {
+		t.Errorf("expected terminal restored before panicking; got:\n%s", out)
+	}
+}
+
+func TestPreview_panicRestores(t *testing.T) {
+
+	s, err := NewSelection([]string{"a", "b"}, []int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetPreview(func(int) string { panic("preview failed") })
+
+	testPanicRestores(t, "preview failed", func(term Terminal) {
+		s.SetTerminal(term)
+		_ = s.Render()
+	})
+}
+
+func TestPreview_compose(t *testing.T) {
+
+	release := make(chan struct{})
+	woken := make(chan struct{}, 1)
+
+	pv := &preview{
+		compute: func(context.Context, int) string {
+			<-release
+			return "first\nsecond\nthird"
+		},
+	}
+	pv.start(func() { woken <- struct{}{} })
+	defer pv.stop()
+
+	options := []string{"a", "b"}
+
+	if width, height := pv.layout(PreviewSide, 20, 2); width != 10 || height != 2 {
+		t.Fatalf("got %dx%d for the options; want 10x2", width, height)
+	}
+
+	pv.show(1)
+	got := pv.compose(options, 10, false, false, "...")
+	if want := []string{"a          | Loading...", "b          | "}; !slices.Equal(got, want) {
+		t.Errorf("loading: got %q; want %q", got, want)
+	}
+
+	close(release)
+	<-woken
+
+	got = pv.compose(options, 10, false, false, "...")
+	if want := []string{"a          | first", "b          | second"}; !slices.Equal(got, want) {
+		t.Errorf("side: got %q; want %q", got, want)
+	}
+
+	pv.scroll(5)
+	got = pv.compose(options, 10, false, false, "...")
+	if want := []string{"a          | second", "b          | third"}; !slices.Equal(got, want) {
+		t.Errorf("scrolled: got %q; want %q", got, want)
+	}
+
+	if width, height := pv.layout(PreviewBottom, 8, 4); width != 8 || height != 2 {
+		t.Fatalf("got %dx%d for the options; want 8x2", width, height)
+	}
+	pv.scroll(-5)
+	got = pv.compose(options, 8, false, false, "...")
+	if want := []string{"a", "b", " ------", " first"}; !slices.Equal(got, want) {
+		t.Errorf("bottom: got %q; want %q", got, want)
+	}
+}
@@ -5,36 +5,12 @@
 package console
 
 import (
+	"context"
 	"fmt"
-	"os"
-
-	"golang.org/x/term"
+	"strconv"
+	"strings"
 )
 
-type selectionTheme struct {
-	Unselected string
-	Selected   string
-}
-
-var selectionThemes = map[Theme]selectionTheme{
-	ThemeNerdFont: {
-		Unselected: "\uEBB5 %s",
-		Selected:   "\u001B[32m\uF058 \u001B[0m%s",
-	},
-	ThemeInverted: {
-		Unselected: "%s",
-		Selected:   "\u001B[7m%s\u001B[0m", // inverted
-	},
-	ThemeColor01: {
-		Unselected: "\u001B[47;30m%s\u001B[0m",   // BG:LightGrey FG:Black
-		Selected:   "\u001B[1;42;30m%s\u001B[0m", // BG:Green FG:White
-	},
-	ThemeAscii: {
-		Unselected: "   %s",
-		Selected:   "> %s",
-	},
-}
-
 func NewSelection[V ~[]E, E any](options []string, values V) (*Selection[E], error) {
 
 	if len(options) != len(values) {
@@ -42,45 +18,137 @@ func NewSelection[V ~[]E, E any](options []string, values V) (*Selection[E], err
 	}
 
 	s := &Selection[E]{
-		values:  values,
-		options: options,
+		list:   list{options: options, filterable: true},
+		values: values,
 	}
 
-	s.SetTheme(defaultTheme)
+	s.SetTheme(defaultThemeName())
 	s.SetShowing(len(options))
 
 	return s, nil
 }
 
+// NewSelectionFromOptions returns a Selection showing options, which can have
+// descriptions and hints, and can be disabled.
+func NewSelectionFromOptions[E any](options []Option[E]) (*Selection[E], error) {
+
+	labels, values, infos := splitOptions(options)
+
+	s, err := NewSelection(labels, values)
+	if err != nil {
+		return nil, err
+	}
+	s.infos = infos
+
+	return s, nil
+}
+
+// NewSelectionFromSource returns a Selection showing the options provided by
+// source, which are fetched while they are shown. A loading indicator is shown
+// while options are fetched. Typing filters the options only when source
+// implements OptionSourceFilter.
+func NewSelectionFromSource[E any](source OptionSource[E]) (*Selection[E], error) {
+
+	if source == nil {
+		return nil, fmt.Errorf("option source must not be nil")
+	}
+
+	pages := newSourceRows(source)
+
+	s := &Selection[E]{
+		list:  list{source: pages, filterable: pages.filterable()},
+		pages: pages,
+	}
+
+	s.SetTheme(defaultThemeName())
+
+	return s, nil
+}
+
+// Group is a heading with the options shown under it, and their values. When
+// Items is not empty, it is used instead of Options and Values.
+type Group[E any] struct {
+	Heading string
+	Options []string
+	Values  []E
+	Items   []Option[E]
+}
+
+// NewGroupedSelection returns a Selection showing the options of each group
+// under its heading. Headings cannot be selected, and are shown above the
+// options of their group, even when scrolled.
+func NewGroupedSelection[E any](groups []Group[E]) (*Selection[E], error) {
+
+	var options []string
+	var values []E
+	var infos []optionInfo
+	var groupOf []int
+	var withItems bool
+	headings := make([]string, 0, len(groups))
+
+	for g, group := range groups {
+		groupOptions, groupValues := group.Options, group.Values
+		groupInfos := make([]optionInfo, len(groupOptions))
+		if len(group.Items) > 0 {
+			groupOptions, groupValues, groupInfos = splitOptions(group.Items)
+			withItems = true
+		}
+
+		if len(groupOptions) != len(groupValues) {
+			return nil, fmt.Errorf("number of options and values of group %q does not match", group.Heading)
+		}
+
+		options = append(options, groupOptions...)
+		values = append(values, groupValues...)
+		infos = append(infos, groupInfos...)
+		for range groupOptions {
+			groupOf = append(groupOf, g)
+		}
+		headings = append(headings, group.Heading)
+	}
+
+	s, err := NewSelection(options, values)
+	if err != nil {
+		return nil, err
+	}
+	s.groups, s.headings = groupOf, headings
+	if withItems {
+		s.infos = infos
+	}
+
+	return s, nil
+}
+
 // Selection represents a selectable list of options with corresponding values.
 // A user can use the Up- and Down-cursor keys to select an option, and push Enter
-// to confirm the selection.
+// to confirm the selection. Typing filters the options, showing those which
+// contain the typed characters in order; Backspace and Escape change or clear
+// the filter.
 //
 // By default, the `ascii` theme is used, but a Nerd Font theme `nerdfont` is also
 // available.
 type Selection[E any] struct {
-	options []string
-	values  []E
+	widget
+	list
 
-	showing int
-	pointer int
-	start   int
-	end     int
+	values []E
+	// pages holds the options when they come from an OptionSource
+	pages *sourceRows[E]
 
 	selectedValue  E
 	selectedOption string
 
-	theme selectionTheme
+	theme Theme
 }
 
+// SetTheme sets the theme used when rendering. When the terminal cannot show
+// the theme, for example because it needs Unicode or colors, a theme which can
+// be shown is used instead. Unknown themes are ignored.
 func (s *Selection[E]) SetTheme(t Theme) {
 
-	theme, ok := selectionThemes[t]
-	if !ok {
-		theme = s.theme
+	if _, ok := themeSpec(t); ok {
+		s.theme = t
 	}
-
-	s.theme = theme
 }
 
 // Selected returns the currently selected option from the Selection.
@@ -94,17 +162,43 @@ func (s *Selection[E]) SelectedOption() string {
 	return s.selectedOption
 }
 
-// SetShowing sets the number of options to be shown in the selection.
-// If n is less than 1, it sets the number of options to the terminal height minus 3.
-// Otherwise, it sets the number of options to n.
-func (s *Selection[E]) SetShowing(n int) {
+// SetPreview sets f to compute the text previewing the value of the option
+// with the pointer, which is shown next to or below the options. It is called
+// in the background, and when the pointer moves on before it returns, its
+// result is not used; use SetPreviewContext to also stop computing it. The
+// preview can be scrolled using Shift with Up, Down, PageUp and PageDown.
+func (s *Selection[E]) SetPreview(f func(value E) string) {
 
-	_, height := TerminalSize()
+	if f == nil {
+		s.preview = nil
+		return
+	}
+
+	s.SetPreviewContext(func(_ context.Context, value E) string {
+		return f(value)
+	})
+}
+
+// SetPreviewContext is like SetPreview, but the context passed to f is
+// cancelled when the pointer moves on before f returns.
+func (s *Selection[E]) SetPreviewContext(f func(ctx context.Context, value E) string) {
 
-	if n < 1 || n > height-3 {
-		s.showing = height - 3
-	} else {
-		s.showing = n
+	if f == nil {
+		s.preview = nil
+		return
+	}
+
+	s.preview = &preview{
+		compute: func(ctx context.Context, option int) string {
+			if s.pages != nil {
+				o, ok := s.pages.option(option)
+				if !ok {
+					return ""
+				}
+				return f(ctx, o.Value)
+			}
+			return f(ctx, s.values[option])
+		},
 	}
 }
 
@@ -123,12 +217,11 @@ func (s *Selection[E]) SetSelected(p int) {
 // name does not exist, the default theme of the Selection is used.
 func (s *Selection[E]) RenderWithTheme(themeName Theme) error {
 
-	theme, ok := selectionThemes[themeName]
-	if !ok {
-		theme = s.theme
+	if _, ok := themeSpec(themeName); !ok {
+		themeName = s.theme
 	}
 
-	return s.render(theme)
+	return s.render(themeName)
 }
 
 // Render renders the Selection.
@@ -136,115 +229,210 @@ func (s *Selection[E]) Render() error {
 	return s.render(s.theme)
 }
 
-func (s *Selection[E]) render(theme selectionTheme) error {
+func (s *Selection[E]) render(themeName Theme) error {
+
+	if s.pages == nil && len(s.options) == 0 {
+		return ErrNoOptions
+	}
 
-	if s.pointer < 0 || s.pointer >= len(s.options) {
+	if s.pointer < 0 || (s.pages == nil && s.pointer >= len(s.options)) {
 		s.pointer = 0
 	}
 
-	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+	t := s.getTerminal()
+
+	if ok, err := s.interactive(t); err != nil {
+		return err
+	} else if !ok && s.pages != nil {
+		return s.renderSourceLines(t)
+	} else if !ok {
+		return s.renderLines(t)
+	}
+
+	sn, err := newSession(t, s.sessionOptions())
 	if err != nil {
-		panic(err)
+		return err
 	}
+	fr := &frame{w: t}
 	defer func() {
-		_ = term.Restore(int(os.Stdin.Fd()), oldState)
-
-		ClearLines(s.showing + 1)
-		showCursor()
+		fr.clear()
+		sn.close()
 	}()
 
-	hideCursor()
+	caps := DetectCapabilities(t)
+	theme := resolveTheme(sn.themeVariant(themeName), caps).Selection
+	s.ellipsis = ellipsis(caps)
+	s.styled = caps.TTY && !caps.Dumb
+	s.unicode = caps.Unicode
+
+	return s.run(t, sn, fr, theme, func(key Key) (bool, error) {
+		switch key.Code {
+		case KeyEnter:
+			return s.chooseRow(s.pointer), nil
+		case KeyMouse:
+			if !key.Mouse.isClick() || !s.inOptions(key.Mouse.X) {
+				break
+			}
+			p, ok := s.optionAt(key.Mouse.Y)
+			if !ok || s.rowDisabled(p) {
+				break
+			}
+			if s.clicks.click(p) && s.chooseRow(p) {
+				return true, nil
+			}
+			s.pointer = p
+		}
 
-	if len(s.options) < s.showing-1 {
-		s.showing = len(s.options)
-	}
+		return false, nil
+	})
+}
 
-	s.renderOptions(theme, s.options, directionNone)
+// renderLines renders the Selection as a numbered list, reading the
+// number of the option, or the option itself, as a line of input.
+func (s *Selection[E]) renderLines(t Terminal) error {
 
-	var done bool
-	for {
-		if done {
-			break
+	digits := len(strconv.Itoa(len(s.options)))
+
+	for i, option := range s.options {
+		if s.groups != nil && (i == 0 || s.groups[i] != s.groups[i-1]) && s.headings[s.groups[i]] != "" {
+			fmt.Fprintln(t, s.headings[s.groups[i]])
 		}
-		b := make([]byte, 3)
-		if _, err := os.Stdin.Read(b); err != nil {
-			return fmt.Errorf("reading input (%w)", err)
+		if s.infos != nil {
+			option = s.infos[i].decorate(option, true, false)
 		}
+		fmt.Fprintf(t, "%*d) %s\n", digits+1, i+1, option)
+	}
 
-		switch {
-		case b[0] == 10 || b[0] == 13:
-			s.selectedValue = s.values[s.pointer]
-			s.selectedOption = s.options[s.pointer]
-			done = true
-		case b[0] == 27 && b[1] == 91:
-			var direction Direction
-
-			switch b[2] {
-			case cursorUp[2]:
-				direction = directionUp
-			case cursorDown[2]:
-				direction = directionDown
-			default:
-				direction = directionNone
-			}
+	// without a query, each row is the option at the same position
+	s.query, s.rows = "", nil
+	s.filter()
+	s.pointer = s.enabledRow(s.pointer, 1)
+
+	prompt := fmt.Sprintf("Enter number [%d]: ", s.pointer+1)
+
+	p, err := promptLine(t, prompt, func(answer string) (int, string) {
+		p := -1
 
-			if direction != directionNone {
-				for i := 0; i < s.showing; i++ {
-					fmt.Print(cursorUp)
+		if answer == "" {
+			p = s.pointer
+		} else if n, err := strconv.Atoi(answer); err == nil {
+			if n < 1 || n > len(s.options) {
+				return -1, fmt.Sprintf("Number must be between 1 and %d", len(s.options))
+			}
+			p = n - 1
+		} else {
+			for i, option := range s.options {
+				if strings.EqualFold(option, answer) {
+					p = i
+					break
 				}
-				s.renderOptions(theme, s.options, direction)
 			}
-		case b[0] == 3 || b[0] == 27:
-			return ErrAborted
 		}
+
+		switch {
+		case p < 0:
+			return -1, fmt.Sprintf("Invalid choice %q", answer)
+		case s.disabled(p):
+			return -1, s.infos[p].refusal(s.options[p])
+		}
+
+		return p, ""
+	})
+	if err != nil {
+		return err
 	}
 
+	s.pointer = p
+	s.choose(p)
+
 	return nil
 }
 
-func (s *Selection[E]) renderOptions(theme selectionTheme, options []string, direction Direction) {
-
-	lenOpts := len(options)
+// chooseRow selects the option in row p, and returns whether it could: options
+// which are disabled or still being fetched cannot be chosen.
+func (s *Selection[E]) chooseRow(p int) bool {
 
-	if direction == directionUp && s.pointer > 0 {
-		s.pointer--
-	} else if direction == directionDown && s.pointer < len(s.options)-1 {
-		s.pointer++
+	if p >= s.rowCount() || s.rowDisabled(p) {
+		return false
 	}
 
-	if direction == directionUp {
-		if s.pointer < s.start {
-			if s.pointer >= lenOpts-s.showing {
-				s.start = lenOpts - s.showing - 1
-			} else {
-				s.start = s.pointer
-			}
-		}
-	} else if direction == directionDown && s.pointer >= s.showing {
-		if s.pointer < lenOpts {
-			s.start = s.pointer - s.showing + 1
+	if s.pages != nil {
+		o, ok := s.pages.option(p)
+		if !ok {
+			return false
 		}
+		s.selectedValue, s.selectedOption = o.Value, o.Label
+		return true
 	}
 
-	s.end = s.start + s.showing
+	s.choose(s.rows[p])
+	return true
+}
 
-	// handle when at end of options
-	if s.end > lenOpts {
-		s.end = lenOpts
-		s.start = s.end - s.showing
-		if s.start < 0 {
-			s.start = 0
-		}
+// renderSourceLines renders the first options of the source as a numbered
+// list, reading the number of the option as a line of input.
+func (s *Selection[E]) renderSourceLines(t Terminal) error {
+
+	ctx := context.Background()
+
+	count, err := s.pages.source.Count(ctx)
+	if err != nil {
+		return err
+	}
+	if count < 1 {
+		return ErrNoOptions
 	}
 
-	for i := s.start; i < s.end; i++ {
+	options, err := s.pages.source.Fetch(ctx, 0, sourcePageSize)
+	if err != nil {
+		return err
+	}
 
-		if i == s.pointer {
-			fmt.Printf("\r\033[2K %s\n",
-				fmt.Sprintf(theme.Selected, options[i]))
-		} else {
-			fmt.Printf("\r\033[2K %s\n",
-				fmt.Sprintf(theme.Unselected, options[i]))
+	digits := len(strconv.Itoa(count))
+
+	for i, o := range options {
+		fmt.Fprintf(t, "%*d) %s\n", digits+1, i+1, o.info().decorate(o.Label, true, false))
+	}
+	if more := count - len(options); more > 0 {
+		fmt.Fprintf(t, "%*s  and %d more\n", digits+1, "", more)
+	}
+
+	var chosen Option[E]
+
+	_, err = promptLine(t, "Enter number [1]: ", func(answer string) (int, string) {
+		n := 1
+		if answer != "" {
+			var err error
+			if n, err = strconv.Atoi(answer); err != nil || n < 1 || n > count {
+				return -1, fmt.Sprintf("Number must be between 1 and %d", count)
+			}
+		}
+
+		fetched, err := s.pages.source.Fetch(ctx, n-1, 1)
+		switch {
+		case err != nil:
+			return -1, err.Error()
+		case len(fetched) == 0:
+			return -1, fmt.Sprintf("Option %d does not exist", n)
+		case fetched[0].Disabled:
+			return -1, fetched[0].info().refusal(fetched[0].Label)
 		}
+
+		chosen = fetched[0]
+		return n - 1, ""
+	})
+	if err != nil {
+		return err
 	}
+
+	s.selectedValue, s.selectedOption = chosen.Value, chosen.Label
+
+	return nil
+}
+
+// choose selects the option at p.
+func (s *Selection[E]) choose(p int) {
+
+	s.selectedValue = s.values[p]
+	s.selectedOption = s.options[p]
 }
@@ -0,0 +1,338 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golistic/console"
+	"github.com/golistic/console/consoletest"
+)
+
+var fruits = []string{"apple", "banana", "cherry", "date", "elderberry", "fig"}
+
+func newFruitSelection(t *testing.T, term *consoletest.Terminal) *console.Selection[int] {
+
+	t.Helper()
+
+	s, err := console.NewSelection(fruits, []int{1, 2, 3, 4, 5, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetTerminal(term)
+
+	return s
+}
+
+func TestSelection_golden(t *testing.T) {
+
+	cases := []struct {
+		name   string
+		width  int
+		height int
+		keys   []string
+	}{
+		{name: "selection_initial", width: 30, height: 10},
+		{name: "selection_down", width: 30, height: 10, keys: []string{consoletest.Down, consoletest.Down}},
+		{name: "selection_scrolled", width: 30, height: 6, keys: []string{consoletest.End}},
+		{name: "selection_filtered", width: 30, height: 10, keys: []string{"e", "r"}},
+		{name: "selection_no_matches", width: 30, height: 10, keys: []string{"x", "y", "z"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(c.width, c.height)
+			term.Type(c.keys...)
+			term.Do(func() {
+				consoletest.Golden(t, c.name, term.Screen().StyledString())
+			})
+			term.Type(consoletest.Escape, consoletest.Escape, consoletest.Escape)
+
+			s := newFruitSelection(t, term)
+			if err := s.RenderWithTheme(console.ThemeAscii); !errors.Is(err, console.ErrAborted) {
+				t.Fatalf("got error %v; want ErrAborted", err)
+			}
+
+			if got := term.Screen().String(); got != "" {
+				t.Errorf("expected screen to be cleared; got:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestSelection_keys(t *testing.T) {
+
+	cases := []struct {
+		name string
+		keys []string
+		want int
+		err  error
+	}{
+		{name: "enter", keys: []string{consoletest.Enter}, want: 1},
+		{name: "down", keys: []string{consoletest.Down, consoletest.Down, consoletest.Enter}, want: 3},
+		{name: "down and up", keys: []string{consoletest.Down, consoletest.Down, consoletest.Up, consoletest.Enter}, want: 2},
+		{name: "end", keys: []string{consoletest.End, consoletest.Enter}, want: 6},
+		{name: "home", keys: []string{consoletest.End, consoletest.Home, consoletest.Enter}, want: 1},
+		{name: "filter", keys: []string{"f", "g", consoletest.Enter}, want: 6},
+		{name: "filter backspace", keys: []string{"f", "x", consoletest.Backspace, consoletest.Enter}, want: 6},
+		{name: "filter without matches", keys: []string{"x", consoletest.Enter, consoletest.Backspace, consoletest.Enter}, want: 1},
+		{name: "escape", keys: []string{consoletest.Down, consoletest.Escape}, err: console.ErrAborted},
+		{name: "interrupt", keys: []string{consoletest.CtrlC}, err: console.ErrAborted},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(30, 10)
+			term.Type(c.keys...)
+
+			s := newFruitSelection(t, term)
+			err := s.RenderWithTheme(console.ThemeAscii)
+			if c.err != nil {
+				if !errors.Is(err, c.err) {
+					t.Fatalf("got error %v; want %v", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := s.Selected(); got != c.want {
+				t.Errorf("got %d; want %d", got, c.want)
+			}
+			if got, want := s.SelectedOption(), fruits[c.want-1]; got != want {
+				t.Errorf("got option %q; want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestSelection_escapeClearsFilter(t *testing.T) {
+
+	term := consoletest.NewTerminal(30, 10)
+	term.Type("f", consoletest.Escape)
+	// let the Escape be read on its own, like a terminal sends it
+	term.Do(func() {})
+	term.Type(consoletest.Up, consoletest.Enter)
+
+	s := newFruitSelection(t, term)
+	if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+		t.Fatal(err)
+	}
+
+	// the pointer stays on fig, and moves on through all options
+	if got, want := s.Selected(), 5; got != want {
+		t.Errorf("got %d; want %d", got, want)
+	}
+}
+
+func TestSelection_mouse(t *testing.T) {
+
+	term := consoletest.NewTerminal(30, 10)
+	term.Type(consoletest.Click(4, 3), consoletest.Click(4, 3))
+
+	s := newFruitSelection(t, term)
+	s.SetMouse(true)
+	if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := s.Selected(), 3; got != want {
+		t.Errorf("got %d; want %d", got, want)
+	}
+}
+
+func TestSelection_lines(t *testing.T) {
+
+	cases := []struct {
+		name  string
+		input string
+		want  int
+		err   bool
+	}{
+		{name: "default", input: "\n", want: 1},
+		{name: "number", input: "4\n", want: 4},
+		{name: "option", input: "Cherry\n", want: 3},
+		{name: "retry out of range", input: "9\n2\n", want: 2},
+		{name: "end of input", input: "nope\n", err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(30, 10)
+			term.SetIsTerminal(false)
+			term.TypeText(c.input)
+
+			s := newFruitSelection(t, term)
+			err := s.RenderWithTheme(console.ThemeAscii)
+			if c.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := s.Selected(); got != c.want {
+				t.Errorf("got %d; want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestGroupedSelection(t *testing.T) {
+
+	newRegions := func(t *testing.T, term *consoletest.Terminal) *console.Selection[string] {
+
+		t.Helper()
+
+		s, err := console.NewGroupedSelection([]console.Group[string]{
+			{Heading: "EU", Options: []string{"frankfurt", "dublin"}, Values: []string{"fra", "dub"}},
+			{Heading: "US", Options: []string{"virginia", "oregon"}, Values: []string{"iad", "pdx"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.SetTerminal(term)
+
+		return s
+	}
+
+	t.Run("pointer skips headings", func(t *testing.T) {
+		term := consoletest.NewTerminal(40, 10)
+		term.Type(consoletest.Down, consoletest.Down)
+		term.Do(func() {
+			want := " -- EU --\n    frankfurt\n    dublin\n -- US --\n > virginia\n    oregon"
+			if got := term.Screen().String(); got != want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+		term.Type(consoletest.Enter)
+
+		s := newRegions(t, term)
+		if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := s.Selected(); got != "iad" {
+			t.Errorf("got %q; want iad", got)
+		}
+	})
+
+	t.Run("lines", func(t *testing.T) {
+		term := consoletest.NewTerminal(40, 10)
+		term.SetIsTerminal(false)
+		term.TypeText("4\n")
+
+		s := newRegions(t, term)
+		if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := s.Selected(); got != "pdx" {
+			t.Errorf("got %q; want pdx", got)
+		}
+		if got := term.Screen().String(); !strings.Contains(got, "EU\n 1) frankfurt") {
+			t.Errorf("expected heading above the options; got:\n%s", got)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		_, err := console.NewGroupedSelection([]console.Group[int]{
+			{Heading: "EU", Options: []string{"frankfurt"}},
+		})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestSelection_narrowFilter(t *testing.T) {
+
+	term := consoletest.NewTerminal(14, 8)
+	term.TypeText("xyz")
+	term.Do(func() {
+		if got, want := term.Screen().String(), " Filter: xyz…"; got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+	term.Type(consoletest.Backspace, consoletest.Backspace, consoletest.Backspace, consoletest.Enter)
+
+	s := newFruitSelection(t, term)
+	if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.Selected(); got != 1 {
+		t.Errorf("got %d; want 1", got)
+	}
+}
+
+func TestGroupedSelection_scrolling(t *testing.T) {
+
+	cases := []struct {
+		name string
+		keys []string
+		want string
+	}{
+		{name: "initial", want: " -- EU --\n > frankfurt\n    dublin"},
+		{name: "down stays", keys: []string{consoletest.Down}, want: " -- EU --\n    frankfurt\n > dublin"},
+		{name: "group end", keys: []string{consoletest.Down, consoletest.Down}, want: " -- EU --\n    dublin\n > lisbon"},
+		{name: "next group", keys: []string{consoletest.Down, consoletest.Down, consoletest.Down}, want: " -- US --\n > virginia"},
+		{name: "up stays", keys: []string{consoletest.Down, consoletest.Down, consoletest.Up}, want: " -- EU --\n > dublin\n    lisbon"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(40, 6)
+			term.Type(c.keys...)
+			term.Do(func() {
+				if got := term.Screen().String(); got != c.want {
+					t.Errorf("got:\n%s\nwant:\n%s", got, c.want)
+				}
+			})
+			term.Type(consoletest.Enter)
+
+			s, err := console.NewGroupedSelection([]console.Group[string]{
+				{Heading: "EU", Options: []string{"frankfurt", "dublin", "lisbon"}, Values: []string{"fra", "dub", "lis"}},
+				{Heading: "US", Options: []string{"virginia", "oregon"}, Values: []string{"iad", "pdx"}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.SetTerminal(term)
+
+			if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSelection_noOptions(t *testing.T) {
+
+	for _, terminal := range []bool{true, false} {
+		t.Run(fmt.Sprintf("terminal %v", terminal), func(t *testing.T) {
+			term := consoletest.NewTerminal(30, 10)
+			term.SetIsTerminal(terminal)
+			term.TypeText("\n")
+
+			s, err := console.NewSelection[[]int](nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.SetTerminal(term)
+
+			if err := s.RenderWithTheme(console.ThemeAscii); !errors.Is(err, console.ErrNoOptions) {
+				t.Errorf("got error %v; want %v", err, console.ErrNoOptions)
+			}
+		})
+	}
+}
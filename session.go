@@ -0,0 +1,269 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ResizeNotifier is implemented by a Terminal which can report changes of
+// its size.
+type ResizeNotifier interface {
+	// NotifyResize sends on ch, without blocking, each time the terminal is
+	// resized until the returned stop function is called.
+	NotifyResize(ch chan<- struct{}) (stop func())
+}
+
+// Suspender is implemented by a Terminal which can suspend the process using
+// it, like the terminal of the process does using job control.
+type Suspender interface {
+	// NotifySuspend sends on ch, without blocking, each time the process is
+	// asked to suspend, for example by SIGTSTP, until the returned stop
+	// function is called.
+	NotifySuspend(ch chan<- struct{}) (stop func())
+	// Suspend suspends the process, returning when it continues.
+	Suspend()
+}
+
+// events reported by a session which are not key presses
+const (
+	keyResize KeyCode = -1 - iota
+	keyCursorPosition
+	keySuspend
+	keyOSC
+	keyDeviceAttributes
+	keyWake
+)
+
+// pollInterval is how often a session checks for events while waiting for input.
+const pollInterval = 50 * time.Millisecond
+
+// session is a widget being rendered interactively: the terminal is in raw
+// mode, and key presses are read together with events like resizing.
+type session struct {
+	term Terminal
+	keys *KeyReader
+	opts sessionOptions
+
+	mu      sync.Mutex
+	restore func() error
+
+	resized    chan struct{}
+	stopResize func()
+
+	// woken is sent on when work done in the background, like computing a
+	// preview, needs the widget to render again
+	woken chan struct{}
+
+	// suspending is set when Ctrl+Z and SIGTSTP suspend the process, which
+	// is only done when the terminal is a Suspender
+	suspending  bool
+	suspend     chan struct{}
+	stopSuspend func()
+
+	// keys read while waiting for the response to a query, and the input
+	// they were decoded from
+	pending      []Key
+	pendingInput []byte
+
+	closeOnce sync.Once
+}
+
+// sessionOptions are the options of a widget which apply to the session.
+type sessionOptions struct {
+	mouse     bool
+	altScreen bool
+	// input was read before the session started, and is decoded first
+	input []byte
+}
+
+// newSession puts t in raw mode and hides the cursor. The returned session
+// must be closed.
+func newSession(t Terminal, opts sessionOptions) (*session, error) {
+
+	sn := &session{
+		term:        t,
+		keys:        NewKeyReader(t),
+		opts:        opts,
+		resized:     make(chan struct{}, 1),
+		stopResize:  func() {},
+		woken:       make(chan struct{}, 1),
+		suspend:     make(chan struct{}, 1),
+		stopSuspend: func() {},
+	}
+
+	sn.keys.buf = opts.input
+
+	if err := sn.enter(); err != nil {
+		return nil, err
+	}
+
+	if rn, ok := t.(ResizeNotifier); ok {
+		sn.stopResize = rn.NotifyResize(sn.resized)
+	}
+
+	if sp, ok := t.(Suspender); ok {
+		sn.suspending = true
+		sn.stopSuspend = sp.NotifySuspend(sn.suspend)
+	}
+
+	guard.add(sn)
+
+	return sn, nil
+}
+
+// enter puts the terminal in raw mode, hides the cursor, and switches to the
+// alternate screen and enables mouse reporting when needed.
+func (sn *session) enter() error {
+
+	restore, err := sn.term.MakeRaw()
+	if err != nil {
+		return fmt.Errorf("setting raw mode (%w)", err)
+	}
+
+	sn.mu.Lock()
+	sn.restore = restore
+	sn.mu.Unlock()
+
+	if sn.opts.altScreen {
+		fmt.Fprint(sn.term, enterAltScreen)
+	}
+
+	hideCursor(sn.term)
+
+	if sn.opts.mouse {
+		// report button presses using SGR encoding
+		fmt.Fprint(sn.term, "\033[?1000h\033[?1006h")
+	}
+
+	return nil
+}
+
+// leave undoes what enter did.
+func (sn *session) leave() {
+
+	sn.mu.Lock()
+	restore := sn.restore
+	sn.restore = nil
+	sn.mu.Unlock()
+
+	if restore == nil {
+		return
+	}
+
+	if sn.opts.mouse {
+		fmt.Fprint(sn.term, "\033[?1006l\033[?1000l")
+	}
+
+	_ = restore()
+	showCursor(sn.term)
+
+	if sn.opts.altScreen {
+		fmt.Fprint(sn.term, leaveAltScreen)
+	}
+}
+
+// close restores the terminal. It can be called more than once, for example
+// by Restore while the widget is still being rendered.
+func (sn *session) close() {
+
+	sn.closeOnce.Do(func() {
+		sn.leave()
+		sn.stopResize()
+		sn.stopSuspend()
+
+		guard.remove(sn)
+	})
+}
+
+// unread returns the input which was read but not handled by a session which
+// did not read keys itself, such as one only asking the terminal for its
+// background. It is lost when the session is closed unless it is passed on.
+func (sn *session) unread() []byte {
+
+	return append(sn.pendingInput, sn.keys.buf...)
+}
+
+// suspendProcess restores the terminal and suspends the process, like
+// pressing Ctrl+Z does in a shell. When the process continues, the terminal
+// is set up again, after which the widget must redraw.
+func (sn *session) suspendProcess() error {
+
+	sn.leave()
+	sn.term.(Suspender).Suspend()
+
+	return sn.enter()
+}
+
+// queryCursor asks the terminal for the position of the cursor, which is
+// reported by readKey using keyCursorPosition.
+func (sn *session) queryCursor() {
+
+	sn.keys.expectCursorPosition()
+	fmt.Fprint(sn.term, "\033[6n")
+}
+
+// wake makes readKey report keyWake, which can be called from any goroutine.
+func (sn *session) wake() {
+
+	select {
+	case sn.woken <- struct{}{}:
+	default:
+	}
+}
+
+// readKey blocks until a key is pressed or the terminal is resized, the latter
+// reported using keyResize. When the widget should suspend the process, which
+// is done using suspendProcess, keySuspend is reported, and after wake was
+// called, keyWake. Waking only interrupts waiting for terminals implementing
+// InputWaiter, such as those returned by NewStreamTerminal, and by NewTerminal
+// on Unix.
+func (sn *session) readKey() (Key, error) {
+
+	if len(sn.pending) > 0 {
+		key := sn.pending[0]
+		sn.pending = sn.pending[1:]
+		return sn.suspendKey(key, nil)
+	}
+
+	waiter, canWait := sn.term.(InputWaiter)
+
+	for {
+		select {
+		case <-sn.resized:
+			return Key{Code: keyResize}, nil
+		case <-sn.suspend:
+			return Key{Code: keySuspend}, nil
+		case <-sn.woken:
+			return Key{Code: keyWake}, nil
+		default:
+		}
+
+		if !canWait || sn.keys.Buffered() {
+			return sn.suspendKey(sn.keys.ReadKey())
+		}
+
+		ok, err := waiter.WaitInput(pollInterval)
+		if err != nil {
+			return Key{}, fmt.Errorf("reading input (%w)", err)
+		}
+
+		if ok {
+			return sn.suspendKey(sn.keys.ReadKey())
+		}
+	}
+}
+
+// suspendKey reports Ctrl+Z as keySuspend when the process can be suspended.
+func (sn *session) suspendKey(key Key, err error) (Key, error) {
+
+	if err == nil && sn.suspending && key.Code == KeyRune && key.Rune == 'z' && key.Mod == ModCtrl {
+		return Key{Code: keySuspend}, nil
+	}
+
+	return key, err
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import "testing"
+
+func TestSession_suspendKey(t *testing.T) {
+
+	ctrlZ := Key{Code: KeyRune, Rune: 'z', Mod: ModCtrl}
+
+	cases := []struct {
+		name       string
+		suspending bool
+		key        Key
+		want       Key
+	}{
+		{name: "ctrl+z suspends", suspending: true, key: ctrlZ, want: Key{Code: keySuspend}},
+		{name: "ctrl+z when not suspending", key: ctrlZ, want: ctrlZ},
+		{name: "z", suspending: true, key: Key{Code: KeyRune, Rune: 'z'}, want: Key{Code: KeyRune, Rune: 'z'}},
+		{name: "ctrl+c", suspending: true, key: Key{Code: KeyRune, Rune: 'c', Mod: ModCtrl},
+			want: Key{Code: KeyRune, Rune: 'c', Mod: ModCtrl}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sn := &session{suspending: c.suspending}
+
+			got, err := sn.suspendKey(c.key, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got %+v; want %+v", got, c.want)
+			}
+		})
+	}
+}
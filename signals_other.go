@@ -0,0 +1,18 @@
+//go:build !unix
+
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"os"
+)
+
+// raise terminates the process like sig would have, since signals cannot be
+// sent to the process itself.
+func raise(os.Signal) {
+
+	os.Exit(1)
+}
@@ -0,0 +1,43 @@
+//go:build unix
+
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// raise sends sig to the process itself. Unless the application relays sig
+// using signal.Notify, its default behavior terminates the process.
+func raise(sig os.Signal) {
+
+	if s, ok := sig.(syscall.Signal); ok {
+		_ = syscall.Kill(os.Getpid(), s)
+	}
+}
+
+// suspendProcess stops the process group, which is the job in the shell,
+// until the process receives SIGCONT, for example when the user brings it
+// back to the foreground. Afterwards, SIGTSTP is relayed to signals again.
+func suspendProcess(signals chan os.Signal) {
+
+	// stopping is asynchronous, so wait until we are continued
+	cont := make(chan os.Signal, 1)
+	signal.Notify(cont, syscall.SIGCONT)
+	defer signal.Stop(cont)
+
+	// stop the whole job using the default action of SIGTSTP, which shells
+	// report as suspended, unlike being stopped by SIGSTOP
+	signal.Reset(syscall.SIGTSTP)
+	_ = syscall.Kill(0, syscall.SIGTSTP)
+	<-cont
+
+	if signals != nil {
+		signal.Notify(signals, syscall.SIGTSTP)
+	}
+}
@@ -0,0 +1,140 @@
+//go:build unix
+
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// countingRestorer counts how often it is closed.
+type countingRestorer struct {
+	closed atomic.Int32
+}
+
+func (cr *countingRestorer) close() {
+
+	cr.closed.Add(1)
+	guard.remove(cr)
+}
+
+// signalChildEnv is set when the test runs as child process, which receives
+// a signal while the application handles it.
+const signalChildEnv = "CONSOLE_TEST_SIGNAL_CHILD"
+
+func TestGuardian_applicationHandler(t *testing.T) {
+
+	if os.Getenv(signalChildEnv) != "" {
+		app := make(chan os.Signal, 2)
+		signal.Notify(app, syscall.SIGTERM)
+
+		cr := &countingRestorer{}
+		guard.add(cr)
+
+		_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+
+		select {
+		case <-app:
+		case <-time.After(5 * time.Second):
+			t.Fatal("application did not receive the signal")
+		}
+
+		// give the guardian time to raise the signal again, which must
+		// not terminate us
+		time.Sleep(200 * time.Millisecond)
+
+		if cr.closed.Load() != 1 {
+			t.Fatal("terminal was not restored")
+		}
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestGuardian_applicationHandler$")
+	cmd.Env = append(os.Environ(), signalChildEnv+"=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("child process failed (%s):\n%s", err, out)
+	}
+}
+
+func TestGuardian_terminates(t *testing.T) {
+
+	if os.Getenv(signalChildEnv) != "" {
+		guard.add(&countingRestorer{})
+
+		_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+		time.Sleep(5 * time.Second)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestGuardian_terminates$")
+	cmd.Env = append(os.Environ(), signalChildEnv+"=1")
+	err := cmd.Run()
+
+	status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+	if err == nil || !ok || !status.Signaled() || status.Signal() != syscall.SIGTERM {
+		t.Fatalf("got %v; want the child terminated by SIGTERM", err)
+	}
+}
+
+func TestHandleSignals(t *testing.T) {
+
+	defer HandleSignals(true)
+
+	cr := &countingRestorer{}
+	guard.add(cr)
+	defer guard.remove(cr)
+
+	HandleSignals(false)
+	if guard.signals != nil {
+		t.Error("expected signals not to be handled")
+	}
+
+	HandleSignals(true)
+	if guard.signals == nil {
+		t.Error("expected signals to be handled while a terminal is guarded")
+	}
+}
+
+func TestSuspendProcess(t *testing.T) {
+
+	if os.Getenv(signalChildEnv) != "" {
+		suspendProcess(nil)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSuspendProcess$")
+	cmd.Env = append(os.Environ(), signalChildEnv+"=1")
+	// the child stops its process group, which must not be ours
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	var status syscall.WaitStatus
+	if _, err := syscall.Wait4(cmd.Process.Pid, &status, syscall.WUNTRACED, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !status.Stopped() || status.StopSignal() != syscall.SIGTSTP {
+		_ = cmd.Process.Kill()
+		t.Fatalf("got status %v; want the child stopped by SIGTSTP", status)
+	}
+
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGCONT); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := syscall.Wait4(cmd.Process.Pid, &status, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !status.Exited() || status.ExitStatus() != 0 {
+		t.Errorf("got status %v; want the child to continue and exit", status)
+	}
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"context"
+	"sync"
+)
+
+// sourcePageSize is the number of options fetched at once from an OptionSource.
+const sourcePageSize = 100
+
+// OptionSource provides the options of a Selection when there are too many
+// to know up front, for example because they are the rows of a database
+// table. Only the options which are shown are fetched.
+//
+// The methods are called from other goroutines than the one rendering, and
+// the context is cancelled when the options are no longer needed.
+type OptionSource[E any] interface {
+	// Count returns the number of options.
+	Count(ctx context.Context) (int, error)
+	// Fetch returns at most limit options, starting with the option at offset.
+	Fetch(ctx context.Context, offset, limit int) ([]Option[E], error)
+}
+
+// OptionSourceFilter is implemented by an OptionSource which can filter its
+// options. Typing only filters the options of a source implementing it.
+type OptionSourceFilter[E any] interface {
+	// Filter returns the source of the options matching query.
+	Filter(query string) OptionSource[E]
+}
+
+// rowSource provides the rows of a list which are not known up front.
+type rowSource interface {
+	// start sets the function called when rows arrive.
+	start(wake func())
+	stop()
+	// setQuery starts over with the rows matching query.
+	setQuery(query string)
+	// count returns the number of rows, which is 0 while counting.
+	count() int
+	// loading returns whether rows are being counted or fetched.
+	loading() bool
+	// fetch starts fetching the rows from start up to end which are not
+	// fetched yet.
+	fetch(start, end int)
+	// row returns the label of row p and what is shown besides it, or false
+	// when it is not fetched yet.
+	row(p int) (string, optionInfo, bool)
+	// err returns why counting or fetching failed.
+	err() error
+}
+
+// sourceRows are the rows of a list fetched from an OptionSource, page by page.
+type sourceRows[E any] struct {
+	source OptionSource[E]
+
+	mu       sync.Mutex
+	wake     func()
+	current  OptionSource[E]
+	ctx      context.Context
+	cancel   context.CancelFunc
+	counted  bool
+	total    int
+	pages    map[int][]Option[E]
+	fetching map[int]bool
+	failed   error
+}
+
+var _ rowSource = (*sourceRows[any])(nil)
+
+func newSourceRows[E any](source OptionSource[E]) *sourceRows[E] {
+
+	return &sourceRows[E]{source: source}
+}
+
+// filterable returns whether the source can filter its options.
+func (sr *sourceRows[E]) filterable() bool {
+
+	_, ok := sr.source.(OptionSourceFilter[E])
+	return ok
+}
+
+func (sr *sourceRows[E]) start(wake func()) {
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.wake = wake
+}
+
+func (sr *sourceRows[E]) stop() {
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if sr.cancel != nil {
+		sr.cancel()
+		sr.cancel = nil
+	}
+}
+
+func (sr *sourceRows[E]) setQuery(query string) {
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if sr.cancel != nil {
+		sr.cancel()
+	}
+
+	sr.current = sr.source
+	if f, ok := sr.source.(OptionSourceFilter[E]); ok && query != "" {
+		sr.current = f.Filter(query)
+	}
+
+	sr.ctx, sr.cancel = context.WithCancel(context.Background())
+	sr.counted, sr.total = false, 0
+	sr.pages, sr.fetching = map[int][]Option[E]{}, map[int]bool{}
+	sr.failed = nil
+
+	ctx, current := sr.ctx, sr.current
+	goSafe(func() {
+		n, err := current.Count(ctx)
+		sr.done(ctx, func() {
+			sr.counted, sr.total = true, max(0, n)
+		}, err)
+	})
+}
+
+// done calls update with the lock held when ctx is still current and there
+// was no error, and wakes up the list.
+func (sr *sourceRows[E]) done(ctx context.Context, update func(), err error) {
+
+	sr.mu.Lock()
+	if ctx.Err() != nil || ctx != sr.ctx {
+		sr.mu.Unlock()
+		return
+	}
+
+	if err != nil {
+		sr.failed = err
+	} else {
+		update()
+	}
+	wake := sr.wake
+	sr.mu.Unlock()
+
+	if wake != nil {
+		wake()
+	}
+}
+
+func (sr *sourceRows[E]) count() int {
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	return sr.total
+}
+
+func (sr *sourceRows[E]) loading() bool {
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	return sr.failed == nil && (!sr.counted || len(sr.fetching) > 0)
+}
+
+// fetch also drops the pages further from start and end than the rows between
+// them, which keeps memory bounded while scrolling through many options.
+func (sr *sourceRows[E]) fetch(start, end int) {
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if !sr.counted || sr.cancel == nil {
+		return
+	}
+
+	end = min(end, sr.total)
+
+	keepFrom, keepTo := start-(end-start), end+(end-start)
+	for page := range sr.pages {
+		if (page+1)*sourcePageSize <= keepFrom || page*sourcePageSize >= keepTo {
+			delete(sr.pages, page)
+		}
+	}
+	for page := start / sourcePageSize; page*sourcePageSize < end; page++ {
+		if _, ok := sr.pages[page]; ok || sr.fetching[page] {
+			continue
+		}
+		sr.fetching[page] = true
+
+		ctx, current := sr.ctx, sr.current
+		goSafe(func() {
+			options, err := current.Fetch(ctx, page*sourcePageSize, sourcePageSize)
+			sr.done(ctx, func() {
+				delete(sr.fetching, page)
+				sr.pages[page] = options
+			}, err)
+		})
+	}
+}
+
+// option returns the option in row p, or false when it is not fetched yet.
+func (sr *sourceRows[E]) option(p int) (Option[E], bool) {
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	page, ok := sr.pages[p/sourcePageSize]
+	if !ok || p%sourcePageSize >= len(page) {
+		return Option[E]{}, false
+	}
+
+	return page[p%sourcePageSize], true
+}
+
+func (sr *sourceRows[E]) row(p int) (string, optionInfo, bool) {
+
+	o, ok := sr.option(p)
+	if !ok {
+		return "", optionInfo{}, false
+	}
+
+	return o.Label, o.info(), true
+}
+
+func (sr *sourceRows[E]) err() error {
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	return sr.failed
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// numberSource provides total options labelled with their number.
+type numberSource struct {
+	total int
+}
+
+func (ns numberSource) Count(context.Context) (int, error) {
+
+	return ns.total, nil
+}
+
+func (ns numberSource) Fetch(_ context.Context, offset, limit int) ([]Option[int], error) {
+
+	var options []Option[int]
+	for i := offset; i < min(offset+limit, ns.total); i++ {
+		options = append(options, Option[int]{Label: fmt.Sprint(i), Value: i})
+	}
+
+	return options, nil
+}
+
+// fetched waits until sr is no longer loading, and returns its pages.
+func fetched(t *testing.T, sr *sourceRows[int], start, end int) []int {
+
+	t.Helper()
+
+	woken := make(chan struct{}, 10)
+	sr.start(func() { woken <- struct{}{} })
+
+	for sr.fetch(start, end); sr.loading(); sr.fetch(start, end) {
+		select {
+		case <-woken:
+		case <-time.After(2 * time.Second):
+			t.Fatal("options not fetched")
+		}
+	}
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	var pages []int
+	for page := range sr.pages {
+		pages = append(pages, page)
+	}
+	slices.Sort(pages)
+
+	return pages
+}
+
+func TestSourceRows_fetch(t *testing.T) {
+
+	sr := newSourceRows[int](numberSource{total: 10 * sourcePageSize})
+	sr.setQuery("")
+	defer sr.stop()
+
+	if got, want := fetched(t, sr, 0, 40), []int{0}; !slices.Equal(got, want) {
+		t.Errorf("got pages %v; want %v", got, want)
+	}
+	if label, _, ok := sr.row(15); !ok || label != "15" {
+		t.Errorf("got row %q, %v; want fetched row 15", label, ok)
+	}
+
+	if got, want := fetched(t, sr, 190, 230), []int{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("got pages %v; want %v", got, want)
+	}
+
+	if got, want := fetched(t, sr, 700, 740), []int{7}; !slices.Equal(got, want) {
+		t.Errorf("got pages %v; want %v; pages far away should be dropped", got, want)
+	}
+}
+
+// panickingSource panics when counting its options.
+type panickingSource struct {
+	numberSource
+}
+
+func (panickingSource) Count(context.Context) (int, error) {
+
+	panic("count failed")
+}
+
+func TestSourceRows_panicRestores(t *testing.T) {
+
+	s, err := NewSelectionFromSource[int](panickingSource{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testPanicRestores(t, "count failed", func(term Terminal) {
+		s.SetTerminal(term)
+		_ = s.Render()
+	})
+}
@@ -0,0 +1,297 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type colorKind uint8
+
+const (
+	colorDefault colorKind = iota
+	colorIndex
+	colorRGB
+)
+
+// Color is a foreground or background color: one of the 16 named colors, an
+// index in the 256-color palette, or an RGB color. The zero value is the
+// default color of the terminal.
+type Color struct {
+	kind    colorKind
+	r, g, b uint8
+}
+
+// The 16 named colors. How they look depends on the palette of the terminal.
+var (
+	Black         = IndexColor(0)
+	Red           = IndexColor(1)
+	Green         = IndexColor(2)
+	Yellow        = IndexColor(3)
+	Blue          = IndexColor(4)
+	Magenta       = IndexColor(5)
+	Cyan          = IndexColor(6)
+	White         = IndexColor(7)
+	BrightBlack   = IndexColor(8)
+	BrightRed     = IndexColor(9)
+	BrightGreen   = IndexColor(10)
+	BrightYellow  = IndexColor(11)
+	BrightBlue    = IndexColor(12)
+	BrightMagenta = IndexColor(13)
+	BrightCyan    = IndexColor(14)
+	BrightWhite   = IndexColor(15)
+)
+
+// IndexColor returns the color with index i in the 256-color palette. The first
+// 16 are the named colors.
+func IndexColor(i uint8) Color {
+
+	return Color{kind: colorIndex, r: i}
+}
+
+// RGBColor returns the color with the given red, green and blue components.
+func RGBColor(r, g, b uint8) Color {
+
+	return Color{kind: colorRGB, r: r, g: g, b: b}
+}
+
+var colorNames = map[string]Color{
+	"black":         Black,
+	"red":           Red,
+	"green":         Green,
+	"yellow":        Yellow,
+	"blue":          Blue,
+	"magenta":       Magenta,
+	"cyan":          Cyan,
+	"white":         White,
+	"brightblack":   BrightBlack,
+	"brightred":     BrightRed,
+	"brightgreen":   BrightGreen,
+	"brightyellow":  BrightYellow,
+	"brightblue":    BrightBlue,
+	"brightmagenta": BrightMagenta,
+	"brightcyan":    BrightCyan,
+	"brightwhite":   BrightWhite,
+}
+
+// ParseColor returns the color described by s, which is one of the names of
+// the 16 named colors like "red" or "bright-red", an index in the 256-color
+// palette like "208", or an RGB color like "#ff8700". An empty string is the
+// default color.
+func ParseColor(s string) (Color, error) {
+
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if s == "" || s == "default" {
+		return Color{}, nil
+	}
+
+	if c, ok := colorNames[strings.NewReplacer("-", "", "_", "", " ", "").Replace(s)]; ok {
+		return c, nil
+	}
+
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil && len(hex) == 6 {
+			return RGBColor(uint8(v>>16), uint8(v>>8), uint8(v)), nil
+		}
+		return Color{}, fmt.Errorf("invalid RGB color %q", s)
+	}
+
+	if i, err := strconv.ParseUint(s, 10, 8); err == nil {
+		return IndexColor(uint8(i)), nil
+	}
+
+	return Color{}, fmt.Errorf("unknown color %q", s)
+}
+
+// IsDefault returns whether c is the default color of the terminal.
+func (c Color) IsDefault() bool {
+
+	return c.kind == colorDefault
+}
+
+// downgrade returns c converted to the closest color the color depth can show.
+func (c Color) downgrade(depth ColorDepth) Color {
+
+	switch {
+	case c.kind == colorDefault || depth == ColorNone:
+		return Color{}
+	case depth == ColorTrue:
+		return c
+	case c.kind == colorRGB && depth == Color256:
+		return IndexColor(rgbToIndex(c.r, c.g, c.b))
+	case c.kind == colorIndex && (depth == Color256 || c.r < 16):
+		return c
+	}
+
+	r, g, b := c.rgb()
+	return IndexColor(nearestColor(r, g, b, 0, 16))
+}
+
+// rgb returns the red, green and blue components of c, using the xterm
+// palette for indexed colors.
+func (c Color) rgb() (r, g, b uint8) {
+
+	if c.kind == colorRGB {
+		return c.r, c.g, c.b
+	}
+
+	return paletteRGB(c.r)
+}
+
+// params returns the SGR parameters setting c as foreground color, or as
+// background color when bg is set.
+func (c Color) params(bg bool) string {
+
+	base := 30
+	if bg {
+		base = 40
+	}
+
+	switch {
+	case c.kind == colorRGB:
+		return strconv.Itoa(base+8) + ";2;" + strconv.Itoa(int(c.r)) + ";" +
+			strconv.Itoa(int(c.g)) + ";" + strconv.Itoa(int(c.b))
+	case c.r < 8:
+		return strconv.Itoa(base + int(c.r))
+	case c.r < 16:
+		return strconv.Itoa(base + 60 + int(c.r) - 8)
+	default:
+		return strconv.Itoa(base+8) + ";5;" + strconv.Itoa(int(c.r))
+	}
+}
+
+var basicPalette = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// paletteRGB returns the components of color i in the xterm 256-color palette.
+func paletteRGB(i uint8) (r, g, b uint8) {
+
+	switch {
+	case i < 16:
+		c := basicPalette[i]
+		return c[0], c[1], c[2]
+	case i < 232:
+		i -= 16
+		return cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6]
+	default:
+		v := 8 + 10*(i-232)
+		return v, v, v
+	}
+}
+
+// rgbToIndex returns the color of the 256-color palette, leaving out the
+// named colors, which is closest to the given one.
+func rgbToIndex(r, g, b uint8) uint8 {
+
+	return nearestColor(r, g, b, 16, 256)
+}
+
+// nearestColor returns the index of the color in the palette which is
+// closest to the given one, looking at indexes from up to, but not including, to.
+func nearestColor(r, g, b uint8, from, to int) uint8 {
+
+	best, bestDistance := from, -1
+	for i := from; i < to; i++ {
+		pr, pg, pb := paletteRGB(uint8(i))
+		dr, dg, db := int(r)-int(pr), int(g)-int(pg), int(b)-int(pb)
+		d := dr*dr + dg*dg + db*db
+		if bestDistance < 0 || d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+
+	return uint8(best)
+}
+
+// Style is how text is shown: its colors and attributes such as bold. When
+// rendered, colors are converted to what the terminal can show, and left out
+// when NO_COLOR is set.
+type Style struct {
+	Foreground    Color
+	Background    Color
+	Bold          bool
+	Dim           bool
+	Italic        bool
+	Underline     bool
+	Strikethrough bool
+	Reverse       bool
+}
+
+// Render returns text using the style, for the standard output of the process.
+func (s Style) Render(text string) string {
+
+	return s.RenderFor(DetectCapabilities(StdTerminal()), text)
+}
+
+// RenderFor returns text using the style, for a terminal with the given
+// capabilities. Nothing is added when the terminal does not support escape
+// sequences, for example because output is not a terminal, unless colors are
+// forced using FORCE_COLOR.
+func (s Style) RenderFor(caps Capabilities, text string) string {
+
+	if (!caps.TTY || caps.Dumb) && caps.Color == ColorNone {
+		return text
+	}
+
+	seq := s.sequence(caps.Color)
+	if seq == "" {
+		return text
+	}
+
+	// styles in text reset with its end, after which ours continues
+	text = strings.ReplaceAll(text, sgrReset, sgrReset+seq)
+
+	return seq + text + sgrReset
+}
+
+const sgrReset = "\033[0m"
+
+// sequence returns the SGR escape sequence setting the style, or an empty
+// string when the style has nothing to set.
+func (s Style) sequence(depth ColorDepth) string {
+
+	var params []string
+
+	for _, attr := range []struct {
+		set   bool
+		param string
+	}{
+		{s.Bold, "1"},
+		{s.Dim, "2"},
+		{s.Italic, "3"},
+		{s.Underline, "4"},
+		{s.Reverse, "7"},
+		{s.Strikethrough, "9"},
+	} {
+		if attr.set {
+			params = append(params, attr.param)
+		}
+	}
+
+	if fg := s.Foreground.downgrade(depth); !fg.IsDefault() {
+		params = append(params, fg.params(false))
+	}
+	if bg := s.Background.downgrade(depth); !bg.IsDefault() {
+		params = append(params, bg.params(true))
+	}
+
+	if len(params) == 0 {
+		return ""
+	}
+
+	return "\033[" + strings.Join(params, ";") + "m"
+}
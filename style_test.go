@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+
+	cases := []struct {
+		s    string
+		want Color
+		err  bool
+	}{
+		{s: "", want: Color{}},
+		{s: "default", want: Color{}},
+		{s: "red", want: Red},
+		{s: " Red ", want: Red},
+		{s: "bright-red", want: BrightRed},
+		{s: "bright_white", want: BrightWhite},
+		{s: "Bright Black", want: BrightBlack},
+		{s: "208", want: IndexColor(208)},
+		{s: "0", want: Black},
+		{s: "#ff8700", want: RGBColor(0xff, 0x87, 0x00)},
+		{s: "#F80", want: RGBColor(0xff, 0x88, 0x00)},
+		{s: "256", err: true},
+		{s: "-1", err: true},
+		{s: "#ff870", err: true},
+		{s: "#gggggg", err: true},
+		{s: "purple", err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			got, err := ParseColor(c.s)
+			if c.err {
+				if err == nil {
+					t.Fatalf("expected error; got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got %+v; want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestColor_downgrade(t *testing.T) {
+
+	cases := []struct {
+		name  string
+		color Color
+		depth ColorDepth
+		want  Color
+	}{
+		{name: "default stays default", color: Color{}, depth: ColorTrue, want: Color{}},
+		{name: "no colors", color: Red, depth: ColorNone, want: Color{}},
+		{name: "true color kept", color: RGBColor(1, 2, 3), depth: ColorTrue, want: RGBColor(1, 2, 3)},
+		{name: "named kept with 16 colors", color: BrightBlue, depth: Color16, want: BrightBlue},
+		{name: "index kept with 256 colors", color: IndexColor(208), depth: Color256, want: IndexColor(208)},
+		{name: "RGB to cube", color: RGBColor(0xff, 0x87, 0x00), depth: Color256, want: IndexColor(208)},
+		{name: "RGB to gray ramp", color: RGBColor(0x80, 0x80, 0x80), depth: Color256, want: IndexColor(244)},
+		{name: "RGB to named", color: RGBColor(250, 10, 10), depth: Color16, want: BrightRed},
+		{name: "dark RGB to black", color: RGBColor(10, 10, 10), depth: Color16, want: Black},
+		{name: "index to named", color: IndexColor(196), depth: Color16, want: BrightRed},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.color.downgrade(c.depth); got != c.want {
+				t.Errorf("got %+v; want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestStyle_RenderFor(t *testing.T) {
+
+	terminal := Capabilities{TTY: true, Color: ColorTrue}
+
+	cases := []struct {
+		name  string
+		style Style
+		caps  Capabilities
+		text  string
+		want  string
+	}{
+		{name: "no style", caps: terminal, text: "a", want: "a"},
+		{name: "bold", style: Style{Bold: true}, caps: terminal, text: "a", want: "\033[1ma\033[0m"},
+		{name: "attributes", style: Style{Dim: true, Italic: true, Underline: true, Reverse: true, Strikethrough: true},
+			caps: terminal, text: "a", want: "\033[2;3;4;7;9ma\033[0m"},
+		{name: "named colors", style: Style{Foreground: Red, Background: BrightWhite}, caps: terminal,
+			text: "a", want: "\033[31;107ma\033[0m"},
+		{name: "256 colors", style: Style{Foreground: IndexColor(208)}, caps: terminal,
+			text: "a", want: "\033[38;5;208ma\033[0m"},
+		{name: "true color", style: Style{Background: RGBColor(1, 2, 3)}, caps: terminal,
+			text: "a", want: "\033[48;2;1;2;3ma\033[0m"},
+		{name: "downgraded", style: Style{Foreground: RGBColor(0xff, 0x87, 0x00)},
+			caps: Capabilities{TTY: true, Color: Color256}, text: "a", want: "\033[38;5;208ma\033[0m"},
+		{name: "no color keeps attributes", style: Style{Bold: true, Foreground: Red},
+			caps: Capabilities{TTY: true}, text: "a", want: "\033[1ma\033[0m"},
+		{name: "only color without colors", style: Style{Foreground: Red},
+			caps: Capabilities{TTY: true}, text: "a", want: "a"},
+		{name: "not a terminal", style: Style{Bold: true}, caps: Capabilities{}, text: "a", want: "a"},
+		{name: "dumb terminal", style: Style{Bold: true}, caps: Capabilities{TTY: true, Dumb: true}, text: "a", want: "a"},
+		{name: "forced colors when not a terminal", style: Style{Bold: true, Foreground: Red},
+			caps: Capabilities{Color: Color16}, text: "a", want: "\033[1;31ma\033[0m"},
+		{name: "nested reset", style: Style{Bold: true}, caps: terminal,
+			text: "x\033[4my\033[0mz", want: "\033[1mx\033[4my\033[0m\033[1mz\033[0m"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.style.RenderFor(c.caps, c.text); got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestStyle_RenderFor_forceColor(t *testing.T) {
+
+	cases := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "piped", want: "a"},
+		{name: "forced", env: map[string]string{"FORCE_COLOR": "1"}, want: "\033[31ma\033[0m"},
+		{name: "forced 256 colors", env: map[string]string{"FORCE_COLOR": "2"}, want: "\033[31ma\033[0m"},
+		{name: "forced off", env: map[string]string{"FORCE_COLOR": "0"}, want: "a"},
+		{name: "no color wins", env: map[string]string{"FORCE_COLOR": "1", "NO_COLOR": "1"}, want: "a"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			caps := detectCapabilities(false, func(name string) string { return c.env[name] })
+
+			if got := (Style{Foreground: Red}).RenderFor(caps, "a"); got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -5,22 +5,229 @@
 package console
 
 import (
+	"io"
 	"os"
+	"sync"
+	"time"
 
 	"golang.org/x/term"
 )
 
+// Terminal is used by widgets to interact with the user: input is read from it,
+// output is written to it, and it is queried for its size and switched to raw mode.
+// Implementations can be backed by a pty, an SSH channel, or a test buffer.
+type Terminal interface {
+	io.Reader
+	io.Writer
+
+	// Size returns the visible dimensions of the terminal.
+	Size() (width, height int, err error)
+
+	// IsTerminal returns whether the input is an interactive terminal.
+	IsTerminal() bool
+
+	// MakeRaw puts the terminal in raw mode and returns a function which
+	// restores the previous state.
+	MakeRaw() (restore func() error, err error)
+}
+
+// NewTerminal returns a Terminal reading from in and writing to out. Raw mode is
+// set on in, while the size is retrieved from out.
+func NewTerminal(in, out *os.File) Terminal {
+
+	return &fileTerminal{in: in, out: out}
+}
+
+// StdTerminal returns the Terminal using the standard input and output of the
+// process. This is the Terminal used by widgets when none is set.
+func StdTerminal() Terminal {
+
+	return NewTerminal(os.Stdin, os.Stdout)
+}
+
+// NewStreamTerminal returns a Terminal reading from r and writing to w. It reports
+// the given size and is considered interactive, but switching to raw mode does
+// nothing; this is left to whatever is on the other side of the streams.
+//
+// While a widget waits for input, r is read in the background. What a read
+// still in progress when the widget returns receives is kept for the next Read,
+// so further input must be read from the returned Terminal instead of from r.
+func NewStreamTerminal(r io.Reader, w io.Writer, width, height int) Terminal {
+
+	return &streamTerminal{
+		Reader: r,
+		Writer: w,
+		width:  width,
+		height: height,
+		// buffered, so a read done after the widget returned is kept
+		chunks: make(chan streamChunk, 1),
+	}
+}
+
 // TerminalSize returns the visible dimensions of the given terminal.
 // When size could not be retrieved, weight 80 and height 24 is returned.
 func TerminalSize() (width, height int) {
 
-	fd := int(os.Stdout.Fd())
+	return terminalSize(StdTerminal())
+}
+
+// terminalSize returns the size of t, or 80 by 24 when it could not be retrieved.
+func terminalSize(t Terminal) (width, height int) {
 
 	var err error
-	width, height, err = term.GetSize(fd)
-	if err != nil {
+	width, height, err = t.Size()
+	if err != nil || width < 1 || height < 1 {
 		return 80, 24
 	}
 
 	return width, height
 }
+
+type fileTerminal struct {
+	in  *os.File
+	out *os.File
+
+	mu sync.Mutex
+
+	// suspend receives SIGTSTP while a widget is rendered
+	suspend chan os.Signal
+}
+
+var _ Terminal = (*fileTerminal)(nil)
+
+func (ft *fileTerminal) Read(p []byte) (int, error) {
+
+	return ft.in.Read(p)
+}
+
+func (ft *fileTerminal) Write(p []byte) (int, error) {
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	return ft.out.Write(p)
+}
+
+func (ft *fileTerminal) Size() (width, height int, err error) {
+
+	return term.GetSize(int(ft.out.Fd()))
+}
+
+func (ft *fileTerminal) IsTerminal() bool {
+
+	return term.IsTerminal(int(ft.in.Fd()))
+}
+
+func (ft *fileTerminal) MakeRaw() (func() error, error) {
+
+	fd := int(ft.in.Fd())
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return term.Restore(fd, oldState)
+	}, nil
+}
+
+type streamTerminal struct {
+	io.Reader
+	io.Writer
+
+	width  int
+	height int
+
+	// input read from Reader in the background, one read at a time, so that
+	// waiting for it can time out; pending is what was received but not yet
+	// read
+	reading bool
+	chunks  chan streamChunk
+	pending []byte
+	err     error
+}
+
+// streamChunk is the result of one read from the Reader of a streamTerminal.
+type streamChunk struct {
+	data []byte
+	err  error
+}
+
+var _ Terminal = (*streamTerminal)(nil)
+var _ InputWaiter = (*streamTerminal)(nil)
+
+func (st *streamTerminal) Read(p []byte) (int, error) {
+
+	if len(st.pending) == 0 && st.err == nil {
+		st.receive(<-st.input())
+	}
+
+	if len(st.pending) == 0 {
+		return 0, st.err
+	}
+
+	n := copy(p, st.pending)
+	st.pending = st.pending[n:]
+
+	return n, nil
+}
+
+// WaitInput waits at most timeout for input and returns whether there is any,
+// which includes reading having failed.
+func (st *streamTerminal) WaitInput(timeout time.Duration) (bool, error) {
+
+	if len(st.pending) > 0 || st.err != nil {
+		return true, nil
+	}
+
+	timer := time.NewTimer(max(0, timeout))
+	defer timer.Stop()
+
+	select {
+	case chunk := <-st.input():
+		st.receive(chunk)
+		return true, nil
+	case <-timer.C:
+		return false, nil
+	}
+}
+
+// input returns the channel receiving what is read from the Reader, starting
+// a read unless one is in progress. The goroutine reading exits when the read
+// is done, so nothing is read when no widget waits for input.
+func (st *streamTerminal) input() <-chan streamChunk {
+
+	if !st.reading {
+		st.reading = true
+		go func() {
+			buf := make([]byte, 256)
+			n, err := st.Reader.Read(buf)
+			st.chunks <- streamChunk{data: buf[:n], err: err}
+		}()
+	}
+
+	return st.chunks
+}
+
+func (st *streamTerminal) receive(chunk streamChunk) {
+
+	st.reading = false
+	st.pending = append(st.pending, chunk.data...)
+	st.err = chunk.err
+}
+
+func (st *streamTerminal) Size() (width, height int, err error) {
+
+	return st.width, st.height, nil
+}
+
+func (st *streamTerminal) IsTerminal() bool {
+
+	return true
+}
+
+func (st *streamTerminal) MakeRaw() (func() error, error) {
+
+	return func() error { return nil }, nil
+}
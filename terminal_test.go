@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTerminalSize(t *testing.T) {
+
+	cases := []struct {
+		name          string
+		width, height int
+		wantWidth     int
+		wantHeight    int
+	}{
+		{name: "reported", width: 100, height: 30, wantWidth: 100, wantHeight: 30},
+		{name: "unknown", width: 0, height: 0, wantWidth: 80, wantHeight: 24},
+		{name: "no height", width: 100, height: 0, wantWidth: 80, wantHeight: 24},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			st := NewStreamTerminal(strings.NewReader(""), io.Discard, c.width, c.height)
+
+			width, height := terminalSize(st)
+			if width != c.wantWidth || height != c.wantHeight {
+				t.Errorf("got %dx%d; want %dx%d", width, height, c.wantWidth, c.wantHeight)
+			}
+		})
+	}
+}
+
+func TestStreamTerminal(t *testing.T) {
+
+	var out bytes.Buffer
+	st := NewStreamTerminal(strings.NewReader("abc"), &out, 80, 24)
+
+	if !st.IsTerminal() {
+		t.Error("expected stream terminal to be interactive")
+	}
+
+	restore, err := st.MakeRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := io.ReadAll(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "abc" {
+		t.Errorf("got %q; want %q", got, "abc")
+	}
+
+	st.Write([]byte("xyz"))
+	if out.String() != "xyz" {
+		t.Errorf("got %q written; want %q", out.String(), "xyz")
+	}
+}
+
+func TestStreamTerminal_WaitInput(t *testing.T) {
+
+	r, w := io.Pipe()
+	st := NewStreamTerminal(r, io.Discard, 80, 24).(*streamTerminal)
+
+	if ok, err := st.WaitInput(10 * time.Millisecond); ok || err != nil {
+		t.Fatalf("got %v, %v; want no input", ok, err)
+	}
+
+	go func() {
+		_, _ = w.Write([]byte("abc"))
+		_ = w.Close()
+	}()
+
+	if ok, err := st.WaitInput(time.Second); !ok || err != nil {
+		t.Fatalf("got %v, %v; want input", ok, err)
+	}
+
+	got, err := io.ReadAll(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "abc" {
+		t.Errorf("got %q; want %q", got, "abc")
+	}
+}
+
+func TestStreamTerminal_stopsReading(t *testing.T) {
+
+	r, w := io.Pipe()
+	defer r.Close()
+
+	s, err := NewSelection([]string{"a", "b"}, []int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetTerminal(NewStreamTerminal(r, io.Discard, 80, 24))
+
+	go func() { _, _ = w.Write([]byte("\r")) }()
+	if err := s.Render(); err != nil {
+		t.Fatal(err)
+	}
+
+	// nothing reads r in the background once the widget returned
+	go func() { _, _ = w.Write([]byte("rest")) }()
+
+	got := make(chan string)
+	go func() {
+		buf := make([]byte, 4)
+		n, _ := io.ReadFull(r, buf)
+		got <- string(buf[:n])
+	}()
+
+	select {
+	case g := <-got:
+		if g != "rest" {
+			t.Errorf("got %q; want %q", g, "rest")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("input was read after the widget returned")
+	}
+}
+
+// lockedBuffer is a bytes.Buffer which can be written and read concurrently.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (lb *lockedBuffer) Write(p []byte) (int, error) {
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	return lb.buf.Write(p)
+}
+
+func (lb *lockedBuffer) String() string {
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	return lb.buf.String()
+}
+
+func TestStreamTerminal_preview(t *testing.T) {
+
+	s, err := NewSelection([]string{"a", "b"}, []int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetPreview(func(v int) string {
+		time.Sleep(20 * time.Millisecond)
+		return "preview of a"
+	})
+
+	r, w := io.Pipe()
+	out := &lockedBuffer{}
+	s.SetTerminal(NewStreamTerminal(r, out, 80, 24))
+
+	done := make(chan error, 1)
+	go func() { done <- s.Render() }()
+
+	// the preview is shown without pressing a key
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(out.String(), "preview of a") {
+		if time.Now().After(deadline) {
+			t.Fatalf("preview not shown; got:\n%q", out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := w.Write([]byte("\r")); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Selected(); got != 1 {
+		t.Errorf("got %d; want 1", got)
+	}
+}
@@ -0,0 +1,105 @@
+//go:build unix
+
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+var _ InputWaiter = (*fileTerminal)(nil)
+
+// WaitInput waits at most timeout for the input to become readable. It does
+// not wait when timeout is not positive.
+func (ft *fileTerminal) WaitInput(timeout time.Duration) (bool, error) {
+
+	fds := []unix.PollFd{{Fd: int32(ft.in.Fd()), Events: unix.POLLIN}}
+
+	// poll waits forever with a negative timeout
+	n, err := unix.Poll(fds, int(max(0, timeout).Milliseconds()))
+	switch {
+	case errors.Is(err, unix.EINTR):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+var _ ResizeNotifier = (*fileTerminal)(nil)
+
+// NotifyResize sends on ch each time the process receives SIGWINCH.
+func (ft *fileTerminal) NotifyResize(ch chan<- struct{}) func() {
+
+	return relaySignal(make(chan os.Signal, 1), ch, syscall.SIGWINCH)
+}
+
+var _ Suspender = (*fileTerminal)(nil)
+
+// NotifySuspend sends on ch each time the process receives SIGTSTP.
+func (ft *fileTerminal) NotifySuspend(ch chan<- struct{}) func() {
+
+	signals := make(chan os.Signal, 1)
+	stop := relaySignal(signals, ch, syscall.SIGTSTP)
+
+	ft.mu.Lock()
+	ft.suspend = signals
+	ft.mu.Unlock()
+
+	return func() {
+		ft.mu.Lock()
+		ft.suspend = nil
+		ft.mu.Unlock()
+
+		stop()
+	}
+}
+
+// Suspend stops the process, together with the other processes of its job,
+// until it is continued.
+func (ft *fileTerminal) Suspend() {
+
+	ft.mu.Lock()
+	signals := ft.suspend
+	ft.mu.Unlock()
+
+	suspendProcess(signals)
+}
+
+// relaySignal relays sig to signals, and sends on ch without blocking each
+// time it is received, until the returned function is called.
+func relaySignal(signals chan os.Signal, ch chan<- struct{}, sig os.Signal) func() {
+
+	done := make(chan struct{})
+
+	signal.Notify(signals, sig)
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
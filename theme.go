@@ -4,9 +4,13 @@
 
 package console
 
-import "strings"
-
-const defaultTheme = "ascii"
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
 
 type Theme string
 
@@ -15,22 +19,331 @@ const (
 	ThemeAscii    Theme = "ascii"
 	ThemeColor01  Theme = "color01"
 	ThemeInverted Theme = "inverted"
+
+	// ThemeColor01Light is the variant of color01 used on light backgrounds.
+	ThemeColor01Light Theme = "color01-light"
 )
 
+// AllTheme are the built-in themes. Use Themes to also get those registered
+// using RegisterTheme.
 var AllTheme = []Theme{
 	ThemeNerdFont,
 	ThemeAscii,
 	ThemeColor01,
 	ThemeInverted,
+	ThemeColor01Light,
+}
+
+// ThemeSpec describes how the widgets look when using a theme.
+type ThemeSpec struct {
+	Selection SelectionTheme
+	Toggle    ToggleTheme
+	Form      FormTheme
+
+	// Requires is what the terminal must support to show the theme.
+	Requires ThemeRequirements
+	// Fallback is the theme used instead when the terminal does not support
+	// what is required. When empty, the ascii theme is used.
+	Fallback Theme
+
+	// Light and Dark are the themes used instead when the background of the
+	// terminal is light or dark. When empty, the theme itself is used.
+	Light Theme
+	Dark  Theme
+}
+
+// SelectionTheme is how the options of a Selection are shown. Both are
+// formats in which %s is replaced by the option. Checked and Unchecked are
+// shown in front of the options of a MultiSelection, and are "[x] " and
+// "[ ] " when empty. Heading is the format of the headings of groups of
+// options, which is "-- %s --" when empty.
+type SelectionTheme struct {
+	Unselected string
+	Selected   string
+	Checked    string
+	Unchecked  string
+	Heading    string
 }
 
+// ToggleTheme is how the options of a Toggle are shown. Both are formats in
+// which %s is replaced by the option. Gap is the number of spaces between
+// both options.
+type ToggleTheme struct {
+	Unselected string
+	Selected   string
+	Gap        int
+}
+
+// FormTheme is how the elements of a Form are shown. Labels are followed by
+// Marker, which is ": " when empty. Hint is used for default values, Input for
+// answers, Info for informational text, and Error for validation errors.
+type FormTheme struct {
+	Label  Style
+	Marker string
+	Prompt Style
+	Hint   Style
+	Input  Style
+	Error  Style
+	Info   Style
+}
+
+// ThemeRequirements is what a theme needs from the terminal.
+type ThemeRequirements struct {
+	// Unicode is set when characters outside ASCII are used.
+	Unicode bool
+	// Color is set when colors are used.
+	Color bool
+	// Styles is set when escape sequences are used, for example to invert
+	// or underline text.
+	Styles bool
+}
+
+var (
+	themesMu sync.RWMutex
+	themes   = map[Theme]ThemeSpec{
+		ThemeNerdFont: {
+			Selection: SelectionTheme{
+				Unselected: "\uEBB5 %s",
+				Selected:   "\u001B[32m\uF058 \u001B[0m%s",
+				Checked:    "\uF14A ",
+				Unchecked:  "\uF096 ",
+				Heading:    "\u001B[2m\u2500\u2500\u001B[0m \u001B[1m%s\u001B[0m \u001B[2m\u2500\u2500\u001B[0m",
+			},
+			Toggle: ToggleTheme{
+				Unselected: "\uEBB5 %s",
+				Selected:   "\u001B[32m\uF058 \u001B[0m%s",
+				Gap:        1,
+			},
+			Form: FormTheme{
+				Label:  Style{Bold: true},
+				Marker: " \uF054 ",
+				Prompt: Style{Foreground: Green},
+				Hint:   Style{Foreground: BrightBlack},
+				Input:  Style{Foreground: Cyan},
+				Error:  Style{Foreground: Red},
+				Info:   Style{Foreground: BrightBlack},
+			},
+			Requires: ThemeRequirements{Unicode: true, Color: true, Styles: true},
+			Fallback: ThemeAscii,
+		},
+		ThemeInverted: {
+			Selection: SelectionTheme{
+				Unselected: "%s",
+				Selected:   "\u001B[7m%s\u001B[0m", // inverted
+				Heading:    "\u001B[1m-- %s --\u001B[0m",
+			},
+			Toggle: ToggleTheme{
+				Unselected: "%s",
+				Selected:   "\u001B[7m%s\u001B[0m", // inverted
+				Gap:        1,
+			},
+			Form: FormTheme{
+				Label: Style{Bold: true},
+				Hint:  Style{Dim: true},
+				Input: Style{Bold: true},
+				Error: Style{Reverse: true},
+				Info:  Style{Dim: true},
+			},
+			Requires: ThemeRequirements{Styles: true},
+			Fallback: ThemeAscii,
+		},
+		ThemeColor01: {
+			Selection: SelectionTheme{
+				Unselected: "\u001B[47;30m%s\u001B[0m",   // BG:LightGrey FG:Black
+				Selected:   "\u001B[1;42;30m%s\u001B[0m", // BG:Green FG:White
+				Heading:    "\u001B[1m-- %s --\u001B[0m",
+			},
+			Toggle: ToggleTheme{
+				Unselected: "\u001B[47;30m%s\u001B[0m",   // BG:LightGrey FG:Black
+				Selected:   "\u001B[1;42;30m%s\u001B[0m", // BG:Green FG:White
+				Gap:        1,
+			},
+			Form: FormTheme{
+				Label: Style{Bold: true},
+				Hint:  Style{Foreground: BrightBlack},
+				Input: Style{Foreground: Green},
+				Error: Style{Foreground: BrightWhite, Background: Red},
+				Info:  Style{Foreground: BrightBlack},
+			},
+			Requires: ThemeRequirements{Color: true, Styles: true},
+			Fallback: ThemeInverted,
+			Light:    ThemeColor01Light,
+		},
+		ThemeColor01Light: {
+			Selection: SelectionTheme{
+				Unselected: "\u001B[100;97m%s\u001B[0m",  // BG:DarkGrey FG:White
+				Selected:   "\u001B[1;42;30m%s\u001B[0m", // BG:Green FG:Black
+				Heading:    "\u001B[1m-- %s --\u001B[0m",
+			},
+			Toggle: ToggleTheme{
+				Unselected: "\u001B[100;97m%s\u001B[0m",  // BG:DarkGrey FG:White
+				Selected:   "\u001B[1;42;30m%s\u001B[0m", // BG:Green FG:Black
+				Gap:        1,
+			},
+			Form: FormTheme{
+				Label: Style{Bold: true},
+				Hint:  Style{Foreground: BrightBlack},
+				Input: Style{Foreground: Blue},
+				Error: Style{Foreground: BrightWhite, Background: Red},
+				Info:  Style{Foreground: BrightBlack},
+			},
+			Requires: ThemeRequirements{Color: true, Styles: true},
+			Fallback: ThemeInverted,
+		},
+		ThemeAscii: {
+			Selection: SelectionTheme{
+				Unselected: "   %s",
+				Selected:   "> %s",
+			},
+			Toggle: ToggleTheme{
+				Unselected: "  %s",
+				Selected:   "> %s",
+				Gap:        2,
+			},
+		},
+	}
+)
+
+// RegisterTheme registers spec as theme name, after which it can be used like
+// the built-in themes. Registering a theme with the name of a registered theme
+// replaces it, but built-in themes cannot be replaced.
+func RegisterTheme(name Theme, spec ThemeSpec) error {
+
+	if name == "" {
+		return fmt.Errorf("theme name must not be empty")
+	}
+
+	if slices.Contains(AllTheme, name) {
+		return fmt.Errorf("theme %q is built in and cannot be replaced", name)
+	}
+
+	formats := []string{
+		spec.Selection.Unselected, spec.Selection.Selected,
+		spec.Toggle.Unselected, spec.Toggle.Selected,
+	}
+	if spec.Selection.Heading != "" {
+		formats = append(formats, spec.Selection.Heading)
+	}
+
+	for _, format := range formats {
+		if strings.Count(format, "%s") != 1 {
+			return fmt.Errorf("theme %q: format %q must contain %%s once", name, format)
+		}
+	}
+
+	themesMu.Lock()
+	defer themesMu.Unlock()
+
+	for _, other := range []struct {
+		kind string
+		name Theme
+	}{
+		{"fallback", spec.Fallback},
+		{"light", spec.Light},
+		{"dark", spec.Dark},
+	} {
+		if _, ok := themes[other.name]; other.name != "" && !ok {
+			return fmt.Errorf("theme %q: %s theme %q does not exist", name, other.kind, other.name)
+		}
+	}
+
+	themes[name] = spec
+
+	return nil
+}
+
+// Themes returns the names of all themes, including those registered using
+// RegisterTheme, sorted by name.
+func Themes() []Theme {
+
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+
+	names := make([]Theme, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	return names
+}
+
+// themeSpec returns the specification of theme t, or false when it does not exist.
+func themeSpec(t Theme) (ThemeSpec, bool) {
+
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+
+	spec, ok := themes[t]
+	return spec, ok
+}
+
+// ThemeLookup returns the theme with the given name, or false when it does not
+// exist. When the standard output cannot show the theme, for example because it
+// needs Unicode or colors, a theme which can be shown is returned instead.
 func ThemeLookup(name string) (Theme, bool) {
-	name = strings.ToLower(name)
-	for _, t := range AllTheme {
-		if string(t) == name {
+	t, ok := findTheme(name)
+	if !ok {
+		return "", false
+	}
+
+	return fallbackTheme(t, DetectCapabilities(StdTerminal())), true
+}
+
+// findTheme returns the theme with the given name, ignoring case.
+func findTheme(name string) (Theme, bool) {
+
+	for _, t := range Themes() {
+		if strings.EqualFold(string(t), name) {
 			return t, true
 		}
 	}
 
 	return "", false
 }
+
+// Supports returns whether a terminal with these capabilities can show theme t.
+func (c Capabilities) Supports(t Theme) bool {
+
+	spec, ok := themeSpec(t)
+	if !ok {
+		return false
+	}
+
+	switch needs := spec.Requires; {
+	case needs.Unicode && !c.Unicode:
+		return false
+	case needs.Color && c.Color == ColorNone:
+		return false
+	case needs.Styles && c.Dumb:
+		return false
+	}
+
+	return true
+}
+
+// fallbackTheme returns t, or the first of its fallbacks which is supported.
+func fallbackTheme(t Theme, caps Capabilities) Theme {
+
+	seen := map[Theme]bool{}
+
+	for !caps.Supports(t) {
+		seen[t] = true
+
+		spec, _ := themeSpec(t)
+		if spec.Fallback == "" || seen[spec.Fallback] {
+			return ThemeAscii
+		}
+		t = spec.Fallback
+	}
+
+	return t
+}
+
+// resolveTheme returns the specification of theme t, or of its fallback when
+// the terminal cannot show it.
+func resolveTheme(t Theme, caps Capabilities) ThemeSpec {
+
+	spec, _ := themeSpec(fallbackTheme(t, caps))
+	return spec
+}
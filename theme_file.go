@@ -0,0 +1,336 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// EnvTheme is the environment variable holding the name of the theme to use
+// by default, or the path of a JSON file defining it.
+const EnvTheme = "CONSOLE_THEME"
+
+// themeFile is the JSON definition of a theme. Values which are not given are
+// taken from the fallback theme.
+type themeFile struct {
+	Name     string `json:"name"`
+	Fallback string `json:"fallback"`
+	Light    string `json:"light"`
+	Dark     string `json:"dark"`
+	Requires *struct {
+		Unicode bool `json:"unicode"`
+		Color   bool `json:"color"`
+		Styles  bool `json:"styles"`
+	} `json:"requires"`
+	Selection *struct {
+		Unselected *string `json:"unselected"`
+		Selected   *string `json:"selected"`
+		Checked    *string `json:"checked"`
+		Unchecked  *string `json:"unchecked"`
+		Heading    *string `json:"heading"`
+	} `json:"selection"`
+	Toggle *struct {
+		Unselected *string `json:"unselected"`
+		Selected   *string `json:"selected"`
+		Gap        *int    `json:"gap"`
+	} `json:"toggle"`
+	Form *struct {
+		Label  *styleFile `json:"label"`
+		Marker *string    `json:"marker"`
+		Prompt *styleFile `json:"prompt"`
+		Hint   *styleFile `json:"hint"`
+		Input  *styleFile `json:"input"`
+		Error  *styleFile `json:"error"`
+		Info   *styleFile `json:"info"`
+	} `json:"form"`
+}
+
+// styleFile is the JSON definition of a Style.
+type styleFile struct {
+	Foreground    string `json:"foreground"`
+	Background    string `json:"background"`
+	Bold          bool   `json:"bold"`
+	Dim           bool   `json:"dim"`
+	Italic        bool   `json:"italic"`
+	Underline     bool   `json:"underline"`
+	Strikethrough bool   `json:"strikethrough"`
+	Reverse       bool   `json:"reverse"`
+}
+
+// LoadTheme registers the theme defined in the JSON file at path, and returns
+// its name. The name is taken from the "name" key, or otherwise from the name
+// of the file without extension.
+func LoadTheme(path string) (Theme, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	name, spec, err := DecodeTheme(f)
+	if err != nil {
+		return "", fmt.Errorf("theme file %s: %w", path, err)
+	}
+
+	if name == "" {
+		name = Theme(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	}
+
+	if err := RegisterTheme(name, spec); err != nil {
+		return "", fmt.Errorf("theme file %s: %w", path, err)
+	}
+
+	return name, nil
+}
+
+// DecodeTheme reads the JSON definition of a theme from r, returning its name,
+// which is empty when not given, and its specification. Values which are not
+// given are taken from the fallback theme, which is ascii by default.
+func DecodeTheme(r io.Reader) (Theme, ThemeSpec, error) {
+
+	var tf themeFile
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&tf); err != nil {
+		return "", ThemeSpec{}, jsonError(err)
+	}
+
+	spec := ThemeSpec{
+		Fallback: Theme(tf.Fallback),
+		Light:    Theme(tf.Light),
+		Dark:     Theme(tf.Dark),
+	}
+
+	base := ThemeAscii
+	if spec.Fallback != "" {
+		base = spec.Fallback
+	}
+	baseSpec, ok := themeSpec(base)
+	if !ok {
+		return "", ThemeSpec{}, fmt.Errorf("fallback: theme %q does not exist", base)
+	}
+	spec.Selection = baseSpec.Selection
+	spec.Toggle = baseSpec.Toggle
+	spec.Form = baseSpec.Form
+
+	if sel := tf.Selection; sel != nil {
+		setString(&spec.Selection.Unselected, sel.Unselected)
+		setString(&spec.Selection.Selected, sel.Selected)
+		setString(&spec.Selection.Checked, sel.Checked)
+		setString(&spec.Selection.Unchecked, sel.Unchecked)
+		setString(&spec.Selection.Heading, sel.Heading)
+	}
+
+	if tg := tf.Toggle; tg != nil {
+		setString(&spec.Toggle.Unselected, tg.Unselected)
+		setString(&spec.Toggle.Selected, tg.Selected)
+		if tg.Gap != nil {
+			if *tg.Gap < 0 {
+				return "", ThemeSpec{}, fmt.Errorf("toggle.gap: must not be negative")
+			}
+			spec.Toggle.Gap = *tg.Gap
+		}
+	}
+
+	for _, format := range []struct {
+		key      string
+		format   string
+		optional bool
+	}{
+		{"selection.unselected", spec.Selection.Unselected, false},
+		{"selection.selected", spec.Selection.Selected, false},
+		{"selection.heading", spec.Selection.Heading, true},
+		{"toggle.unselected", spec.Toggle.Unselected, false},
+		{"toggle.selected", spec.Toggle.Selected, false},
+	} {
+		if format.optional && format.format == "" {
+			continue
+		}
+		if strings.Count(format.format, "%s") != 1 {
+			return "", ThemeSpec{}, fmt.Errorf("%s: format %q must contain %%s once",
+				format.key, format.format)
+		}
+	}
+
+	if form := tf.Form; form != nil {
+		setString(&spec.Form.Marker, form.Marker)
+
+		for _, style := range []struct {
+			key  string
+			file *styleFile
+			dest *Style
+		}{
+			{"form.label", form.Label, &spec.Form.Label},
+			{"form.prompt", form.Prompt, &spec.Form.Prompt},
+			{"form.hint", form.Hint, &spec.Form.Hint},
+			{"form.input", form.Input, &spec.Form.Input},
+			{"form.error", form.Error, &spec.Form.Error},
+			{"form.info", form.Info, &spec.Form.Info},
+		} {
+			if style.file == nil {
+				continue
+			}
+			s, err := style.file.style(style.key)
+			if err != nil {
+				return "", ThemeSpec{}, err
+			}
+			*style.dest = s
+		}
+	}
+
+	if tf.Requires != nil {
+		spec.Requires = ThemeRequirements{
+			Unicode: tf.Requires.Unicode,
+			Color:   tf.Requires.Color,
+			Styles:  tf.Requires.Styles,
+		}
+	} else {
+		spec.Requires = spec.requirements()
+	}
+
+	return Theme(tf.Name), spec, nil
+}
+
+// requirements returns what the theme needs from the terminal judging from
+// the characters and escape sequences it uses.
+func (spec ThemeSpec) requirements() ThemeRequirements {
+
+	var req ThemeRequirements
+
+	for _, format := range []string{
+		spec.Selection.Unselected, spec.Selection.Selected,
+		spec.Selection.Checked, spec.Selection.Unchecked, spec.Selection.Heading,
+		spec.Toggle.Unselected, spec.Toggle.Selected,
+		spec.Form.Marker,
+	} {
+		for _, r := range StripANSI(format) {
+			if r > 0x7f {
+				req.Unicode = true
+			}
+		}
+		for _, seq := range ansiSequence.FindAllString(format, -1) {
+			req.Styles = true
+			req.Color = req.Color || sgrColorParam.MatchString(seq)
+		}
+	}
+
+	for _, style := range []Style{
+		spec.Form.Label, spec.Form.Prompt, spec.Form.Hint,
+		spec.Form.Input, spec.Form.Error, spec.Form.Info,
+	} {
+		if style != (Style{}) {
+			req.Styles = true
+		}
+		if !style.Foreground.IsDefault() || !style.Background.IsDefault() {
+			req.Color = true
+		}
+	}
+
+	return req
+}
+
+// sgrColorParam matches SGR parameters setting a color.
+var sgrColorParam = regexp.MustCompile(`[\[;](3[0-8]|4[0-8]|9[0-7]|10[0-7])[;m]`)
+
+func setString(dest *string, format *string) {
+
+	if format != nil {
+		*dest = *format
+	}
+}
+
+func (sf *styleFile) style(key string) (Style, error) {
+
+	fg, err := ParseColor(sf.Foreground)
+	if err != nil {
+		return Style{}, fmt.Errorf("%s.foreground: %w", key, err)
+	}
+
+	bg, err := ParseColor(sf.Background)
+	if err != nil {
+		return Style{}, fmt.Errorf("%s.background: %w", key, err)
+	}
+
+	return Style{
+		Foreground:    fg,
+		Background:    bg,
+		Bold:          sf.Bold,
+		Dim:           sf.Dim,
+		Italic:        sf.Italic,
+		Underline:     sf.Underline,
+		Strikethrough: sf.Strikethrough,
+		Reverse:       sf.Reverse,
+	}, nil
+}
+
+// jsonError returns err with the key the value of which has the wrong type.
+func jsonError(err error) error {
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return fmt.Errorf("%s: expected %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+
+	return fmt.Errorf("invalid JSON: %s", strings.TrimPrefix(err.Error(), "json: "))
+}
+
+var envThemes = struct {
+	sync.Mutex
+	loaded map[string]Theme
+}{loaded: map[string]Theme{}}
+
+// ThemeFromEnv returns the theme set using the environment variable
+// CONSOLE_THEME, which holds either the name of a theme, or the path of a JSON
+// file defining one. When it is not set, the ascii theme is returned.
+//
+// The theme is the default of widgets and forms, but is not used when a theme
+// is chosen using for example SetTheme.
+func ThemeFromEnv() (Theme, error) {
+
+	value := os.Getenv(EnvTheme)
+	if value == "" {
+		return ThemeAscii, nil
+	}
+
+	if t, ok := findTheme(value); ok {
+		return t, nil
+	}
+
+	envThemes.Lock()
+	defer envThemes.Unlock()
+
+	if t, ok := envThemes.loaded[value]; ok {
+		return t, nil
+	}
+
+	if _, err := os.Stat(value); err != nil {
+		return ThemeAscii, fmt.Errorf("%s: theme %q does not exist", EnvTheme, value)
+	}
+
+	t, err := LoadTheme(value)
+	if err != nil {
+		return ThemeAscii, fmt.Errorf("%s: %w", EnvTheme, err)
+	}
+	envThemes.loaded[value] = t
+
+	return t, nil
+}
+
+// defaultThemeName returns the theme used when none was chosen.
+func defaultThemeName() Theme {
+
+	t, _ := ThemeFromEnv()
+	return t
+}
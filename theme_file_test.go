@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecodeTheme(t *testing.T) {
+
+	t.Run("values taken from fallback", func(t *testing.T) {
+		name, spec, err := DecodeTheme(strings.NewReader(`{
+			"name": "mine",
+			"fallback": "nerdfont",
+			"selection": {"selected": "=> %s"},
+			"toggle": {"gap": 2},
+			"form": {"label": {"foreground": "cyan", "bold": true}}
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if name != "mine" {
+			t.Errorf("got name %q; want %q", name, "mine")
+		}
+
+		nerd, _ := themeSpec(ThemeNerdFont)
+		if got, want := spec.Selection.Selected, "=> %s"; got != want {
+			t.Errorf("got selected %q; want %q", got, want)
+		}
+		if got, want := spec.Selection.Unselected, nerd.Selection.Unselected; got != want {
+			t.Errorf("got unselected %q; want %q from fallback", got, want)
+		}
+		if got, want := spec.Toggle.Gap, 2; got != want {
+			t.Errorf("got gap %d; want %d", got, want)
+		}
+		if got, want := spec.Form.Label, (Style{Foreground: Cyan, Bold: true}); got != want {
+			t.Errorf("got label %+v; want %+v", got, want)
+		}
+		if !spec.Requires.Unicode {
+			t.Error("expected Unicode to be required, like the fallback")
+		}
+	})
+
+	t.Run("requirements derived", func(t *testing.T) {
+		_, spec, err := DecodeTheme(strings.NewReader(`{
+			"selection": {"selected": "\u001b[32m> %s\u001b[0m"}
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := (ThemeRequirements{Styles: true, Color: true}); spec.Requires != want {
+			t.Errorf("got %+v; want %+v", spec.Requires, want)
+		}
+	})
+
+	t.Run("requirements given", func(t *testing.T) {
+		_, spec, err := DecodeTheme(strings.NewReader(`{
+			"selection": {"selected": "\u001b[32m> %s\u001b[0m"},
+			"requires": {"unicode": true}
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := (ThemeRequirements{Unicode: true}); spec.Requires != want {
+			t.Errorf("got %+v; want %+v", spec.Requires, want)
+		}
+	})
+}
+
+func TestDecodeTheme_errors(t *testing.T) {
+
+	cases := []struct {
+		name string
+		json string
+		want string
+	}{
+		{name: "invalid JSON", json: `{"name": `, want: "invalid JSON"},
+		{name: "unknown key", json: `{"colour": "red"}`, want: `unknown field "colour"`},
+		{name: "wrong type", json: `{"toggle": {"gap": "wide"}}`, want: "toggle.gap: expected int"},
+		{name: "unknown fallback", json: `{"fallback": "nope"}`, want: `fallback: theme "nope" does not exist`},
+		{name: "negative gap", json: `{"toggle": {"gap": -1}}`, want: "toggle.gap: must not be negative"},
+		{name: "selected without verb", json: `{"selection": {"selected": "> "}}`, want: "selection.selected: format"},
+		{name: "unselected with two verbs", json: `{"selection": {"unselected": "%s %s"}}`, want: "selection.unselected: format"},
+		{name: "heading without verb", json: `{"selection": {"heading": "--"}}`, want: "selection.heading: format"},
+		{name: "toggle selected", json: `{"toggle": {"selected": "x"}}`, want: "toggle.selected: format"},
+		{name: "toggle unselected", json: `{"toggle": {"unselected": "x"}}`, want: "toggle.unselected: format"},
+		{name: "foreground", json: `{"form": {"label": {"foreground": "purple"}}}`, want: "form.label.foreground: unknown color"},
+		{name: "background", json: `{"form": {"error": {"background": "#12"}}}`, want: "form.error.background: invalid RGB color"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, _, err := DecodeTheme(strings.NewReader(c.json))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), c.want) {
+				t.Errorf("got error %q; want it to contain %q", err, c.want)
+			}
+		})
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "test-load-theme.json")
+	if err := os.WriteFile(path, []byte(`{"selection": {"selected": "* %s"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	name, err := LoadTheme(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "test-load-theme" {
+		t.Errorf("got name %q; want name of the file", name)
+	}
+
+	spec, ok := themeSpec(name)
+	if !ok {
+		t.Fatal("expected theme to be registered")
+	}
+	if got, want := spec.Selection.Selected, "* %s"; got != want {
+		t.Errorf("got selected %q; want %q", got, want)
+	}
+
+	if _, err := LoadTheme(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestThemeFromEnv(t *testing.T) {
+
+	cases := []struct {
+		name  string
+		value string
+		want  Theme
+		err   bool
+	}{
+		{name: "not set", value: "", want: ThemeAscii},
+		{name: "built-in", value: "nerdfont", want: ThemeNerdFont},
+		{name: "unknown", value: "nope", want: ThemeAscii, err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(EnvTheme, c.value)
+
+			got, err := ThemeFromEnv()
+			if (err != nil) != c.err {
+				t.Fatalf("got error %v; want error %v", err, c.err)
+			}
+			if got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRegisterTheme(t *testing.T) {
+
+	valid := ThemeSpec{
+		Selection: SelectionTheme{Unselected: "  %s", Selected: "> %s"},
+		Toggle:    ToggleTheme{Unselected: "%s", Selected: "[%s]"},
+	}
+
+	with := func(change func(spec *ThemeSpec)) ThemeSpec {
+		spec := valid
+		change(&spec)
+		return spec
+	}
+
+	cases := []struct {
+		name  string
+		theme Theme
+		spec  ThemeSpec
+		want  string
+	}{
+		{name: "valid", theme: "test-register-valid", spec: valid},
+		{name: "empty name", theme: "", spec: valid, want: "must not be empty"},
+		{name: "built-in", theme: ThemeAscii, spec: valid, want: "built in"},
+		{
+			name:  "selection without verb",
+			theme: "test-register-invalid",
+			spec:  with(func(spec *ThemeSpec) { spec.Selection.Selected = "> " }),
+			want:  "must contain %s once",
+		},
+		{
+			name:  "selection with two verbs",
+			theme: "test-register-invalid",
+			spec:  with(func(spec *ThemeSpec) { spec.Selection.Unselected = "%s %s" }),
+			want:  "must contain %s once",
+		},
+		{
+			name:  "toggle without verb",
+			theme: "test-register-invalid",
+			spec:  with(func(spec *ThemeSpec) { spec.Toggle.Unselected = "" }),
+			want:  "must contain %s once",
+		},
+		{
+			name:  "heading without verb",
+			theme: "test-register-invalid",
+			spec:  with(func(spec *ThemeSpec) { spec.Selection.Heading = "--" }),
+			want:  "must contain %s once",
+		},
+		{
+			name:  "unknown fallback",
+			theme: "test-register-invalid",
+			spec:  with(func(spec *ThemeSpec) { spec.Fallback = "nope" }),
+			want:  `fallback theme "nope" does not exist`,
+		},
+		{
+			name:  "unknown light",
+			theme: "test-register-invalid",
+			spec:  with(func(spec *ThemeSpec) { spec.Light = "nope" }),
+			want:  `light theme "nope" does not exist`,
+		},
+		{
+			name:  "unknown dark",
+			theme: "test-register-invalid",
+			spec:  with(func(spec *ThemeSpec) { spec.Dark = "nope" }),
+			want:  `dark theme "nope" does not exist`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := RegisterTheme(c.theme, c.spec)
+			if c.want == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("got error %v; want it to contain %q", err, c.want)
+			}
+			if c.theme != "" && c.theme != ThemeAscii {
+				if _, ok := themeSpec(c.theme); ok {
+					t.Error("expected invalid theme not to be registered")
+				}
+			}
+		})
+	}
+
+	if spec, _ := themeSpec(ThemeAscii); spec.Selection.Selected != "> %s" {
+		t.Error("expected built-in theme not to be replaced")
+	}
+}
+
+func TestThemeLookup_registered(t *testing.T) {
+
+	err := RegisterTheme("test-lookup-Corporate", ThemeSpec{
+		Selection: SelectionTheme{Unselected: "  %s", Selected: "* %s"},
+		Toggle:    ToggleTheme{Unselected: "%s", Selected: "[%s]"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := ThemeLookup("test-lookup-corporate"); !ok || got != "test-lookup-Corporate" {
+		t.Errorf("got %q, %v; want the registered theme", got, ok)
+	}
+
+	// the demo lists these as the themes which can be chosen
+	if !slices.Contains(Themes(), "test-lookup-Corporate") {
+		t.Errorf("expected registered theme in %v", Themes())
+	}
+	for _, builtIn := range AllTheme {
+		if !slices.Contains(Themes(), builtIn) {
+			t.Errorf("expected built-in theme %q in %v", builtIn, Themes())
+		}
+	}
+}
@@ -6,41 +6,9 @@ package console
 
 import (
 	"fmt"
-	"os"
 	"strings"
-
-	"golang.org/x/term"
 )
 
-type toggleTheme struct {
-	Unselected string
-	Selected   string
-	Gap        int
-}
-
-var toggleThemes = map[Theme]toggleTheme{
-	ThemeNerdFont: {
-		Unselected: "\uEBB5 %s",
-		Selected:   "\u001B[32m\uF058 \u001B[0m%s",
-		Gap:        1,
-	},
-	ThemeInverted: {
-		Unselected: "%s",
-		Selected:   "\u001B[7m%s\u001B[0m", // inverted
-		Gap:        1,
-	},
-	ThemeColor01: {
-		Unselected: "\u001B[47;30m%s\u001B[0m",   // BG:LightGrey FG:Black
-		Selected:   "\u001B[1;42;30m%s\u001B[0m", // BG:Green FG:White
-		Gap:        1,
-	},
-	ThemeAscii: {
-		Unselected: "  %s",
-		Selected:   "> %s",
-		Gap:        2,
-	},
-}
-
 func NewToggle[V ~[]T, T comparable](label string, options []string, values V) (*Toggle[T], error) {
 
 	if len(options) != 2 || len(options) != len(values) {
@@ -53,12 +21,30 @@ func NewToggle[V ~[]T, T comparable](label string, options []string, values V) (
 		values:  values,
 	}
 
-	toggle.SetTheme(defaultTheme)
+	toggle.SetTheme(defaultThemeName())
 
 	return toggle, nil
 }
 
+// NewToggleFromOptions returns a Toggle between two options, which can have
+// descriptions and hints, and can be disabled. The description of the option
+// which is toggled on is shown after both options.
+func NewToggleFromOptions[T comparable](label string, options []Option[T]) (*Toggle[T], error) {
+
+	labels, values, infos := splitOptions(options)
+
+	tg, err := NewToggle(label, labels, values)
+	if err != nil {
+		return nil, err
+	}
+	tg.infos = infos
+
+	return tg, nil
+}
+
 type Toggle[T comparable] struct {
+	widget
+
 	label   string
 	options []string
 	values  []T
@@ -66,18 +52,31 @@ type Toggle[T comparable] struct {
 	pointer        int
 	selectedOption T
 
-	theme toggleTheme
+	// infos holds what is shown besides the label of each option, and is nil
+	// when there is nothing; styled is set when the terminal can show styles
+	infos    []optionInfo
+	styled   bool
+	ellipsis string
+
+	theme Theme
 	gap   int
+
+	// row on the screen, starting at 1, and the columns where each option
+	// starts and ends, or ends before it starts when cut off; row is 0 when
+	// not known
+	row     int
+	columns [2][2]int
+	clicks  clicks
 }
 
+// SetTheme sets the theme used when rendering. When the terminal cannot show
+// the theme, for example because it needs Unicode or colors, a theme which can
+// be shown is used instead. Unknown themes are ignored.
 func (tg *Toggle[E]) SetTheme(t Theme) {
 
-	theme, ok := toggleThemes[t]
-	if !ok {
-		theme = tg.theme
+	if _, ok := themeSpec(t); ok {
+		tg.theme = t
 	}
-
-	tg.theme = theme
 }
 
 // Selected returns the currently toggled option.
@@ -104,12 +103,11 @@ func (tg *Toggle[T]) Label() string {
 // name does not exist, the default theme of the Selection is used.
 func (tg *Toggle[T]) RenderWithTheme(t Theme) error {
 
-	theme, ok := toggleThemes[t]
-	if !ok {
-		theme = tg.theme
+	if _, ok := themeSpec(t); !ok {
+		t = tg.theme
 	}
 
-	return tg.render(theme)
+	return tg.render(t)
 }
 
 // Render renders the Selection.
@@ -117,66 +115,225 @@ func (tg *Toggle[T]) Render() error {
 	return tg.render(tg.theme)
 }
 
-func (tg *Toggle[T]) render(theme toggleTheme) error {
+func (tg *Toggle[T]) render(themeName Theme) error {
+
+	t := tg.getTerminal()
 
-	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if ok, err := tg.interactive(t); err != nil {
+		return err
+	} else if !ok {
+		return tg.renderLines(t)
+	}
+
+	sn, err := newSession(t, tg.sessionOptions())
 	if err != nil {
-		panic(err)
+		return err
 	}
+	fr := &frame{w: t, inline: true}
 	defer func() {
-		_ = term.Restore(int(os.Stdin.Fd()), oldState)
-
-		fmt.Print("\r\033[2K")
-		showCursor()
+		fr.clear()
+		sn.close()
 	}()
 
-	hideCursor()
-	tg.renderOptions(tg.theme, tg.options)
+	caps := DetectCapabilities(t)
+	theme := resolveTheme(sn.themeVariant(themeName), caps).Toggle
+	tg.styled = caps.TTY && !caps.Dumb
+	tg.ellipsis = ellipsis(caps)
+
+	if tg.disabled(tg.pointer) {
+		tg.pointer = 1 - tg.pointer
+	}
+
+	tg.renderOptions(fr, theme, tg.options)
+	tg.locate(sn)
 
-	var done bool
 	for {
-		if done {
-			break
-		}
-		b := make([]byte, 3)
-		if _, err := os.Stdin.Read(b); err != nil {
-			return fmt.Errorf("reading input (%w)", err)
+		key, err := sn.readKey()
+		if err != nil {
+			return err
 		}
 
-		switch {
-		case b[0] == 10 || b[0] == 13:
+		switch key.Code {
+		case KeyEnter:
+			if tg.disabled(tg.pointer) {
+				continue
+			}
 			tg.selectedOption = tg.values[tg.pointer]
-			done = true
-		case b[0] == 27 && b[1] == 91:
-			switch b[2] {
-			case cursorLeft[2]:
-				tg.pointer = 0
-			case cursorRight[2]:
-				tg.pointer = 1
+			return nil
+		case KeyLeft, KeyHome:
+			tg.moveTo(0)
+		case KeyRight, KeyEnd:
+			tg.moveTo(1)
+		case KeyTab:
+			tg.moveTo(1 - tg.pointer)
+		case keyResize:
+			fr.invalidate()
+			tg.locate(sn)
+		case keySuspend:
+			fr.clear()
+			if err := sn.suspendProcess(); err != nil {
+				return err
 			}
-
-			tg.renderOptions(theme, tg.options)
-		case b[0] == 3 || b[0] == 27:
+			tg.locate(sn)
+		case keyCursorPosition:
+			tg.row = key.row
+			continue
+		case KeyMouse:
+			switch m := key.Mouse; {
+			case m.Button == MouseWheelUp || m.Button == MouseWheelDown:
+				tg.moveTo(1 - tg.pointer)
+			case m.isClick():
+				p, ok := tg.optionAt(m.X, m.Y)
+				if !ok || tg.disabled(p) {
+					continue
+				}
+				tg.pointer = p
+				if tg.clicks.click(p) {
+					tg.selectedOption = tg.values[p]
+					return nil
+				}
+			default:
+				continue
+			}
+		case KeyEscape:
 			return ErrAborted
+		default:
+			if key.IsInterrupt() {
+				return ErrAborted
+			}
+			continue
 		}
+
+		tg.renderOptions(fr, theme, tg.options)
 	}
+}
+
+// moveTo toggles option p on, unless it is disabled.
+func (tg *Toggle[T]) moveTo(p int) {
+
+	if !tg.disabled(p) {
+		tg.pointer = p
+	}
+}
+
+// disabled returns whether option p is disabled.
+func (tg *Toggle[T]) disabled(p int) bool {
+
+	return tg.infos != nil && tg.infos[p].disabled
+}
+
+// renderLines prompts for one of the options as a line of input. Besides
+// the options themselves, y/yes and n/no choose the first and second option.
+func (tg *Toggle[T]) renderLines(t Terminal) error {
+
+	if tg.disabled(tg.pointer) {
+		tg.pointer = 1 - tg.pointer
+	}
+
+	prompt := fmt.Sprintf("%s (%s/%s) [%s]: ", tg.label,
+		tg.options[0], tg.options[1], tg.options[tg.pointer])
+
+	p, err := promptLine(t, prompt, func(answer string) (int, string) {
+		p := -1
+
+		switch strings.ToLower(answer) {
+		case "":
+			p = tg.pointer
+		case "y", "yes", "1":
+			p = 0
+		case "n", "no", "2":
+			p = 1
+		}
+
+		for i, option := range tg.options {
+			if p < 0 && strings.EqualFold(option, answer) {
+				p = i
+			}
+		}
+
+		switch {
+		case p < 0:
+			return -1, fmt.Sprintf("Answer must be %s or %s", tg.options[0], tg.options[1])
+		case tg.disabled(p):
+			return -1, tg.infos[p].refusal(tg.options[p])
+		}
+
+		return p, ""
+	})
+	if err != nil {
+		return err
+	}
+
+	tg.pointer = p
+	tg.selectedOption = tg.values[p]
 
 	return nil
 }
 
-func (tg *Toggle[T]) renderOptions(theme toggleTheme, options []string) {
+func (tg *Toggle[T]) renderOptions(fr *frame, theme ToggleTheme, options []string) {
 
-	fmt.Printf("\r\033[2K%s ", tg.label)
+	var description string
+	if tg.infos != nil {
+		options = []string{
+			tg.infos[0].decorate(options[0], false, tg.styled),
+			tg.infos[1].decorate(options[1], false, tg.styled),
+		}
+		if d := tg.infos[tg.pointer].description; d != "" {
+			description = " " + dim(d, tg.styled)
+		}
+	}
 
+	var first, second string
 	if tg.pointer == 0 {
-		fmt.Printf("%s%s%s",
-			fmt.Sprintf(theme.Selected, options[0]),
-			strings.Repeat(" ", theme.Gap),
-			fmt.Sprintf(theme.Unselected, options[1]))
+		first = fmt.Sprintf(theme.Selected, options[0])
+		second = fmt.Sprintf(theme.Unselected, options[1])
 	} else {
-		fmt.Printf("%s%s%s",
-			fmt.Sprintf(theme.Unselected, options[0]),
-			strings.Repeat(" ", theme.Gap),
-			fmt.Sprintf(theme.Selected, options[1]))
+		first = fmt.Sprintf(theme.Unselected, options[0])
+		second = fmt.Sprintf(theme.Selected, options[1])
 	}
+
+	width, _ := terminalSize(tg.getTerminal())
+	line := tg.label + " " + first + strings.Repeat(" ", theme.Gap) + second + description
+	shown := Truncate(line, width-1, tg.ellipsis)
+	fr.render([]string{shown})
+
+	// options can only be clicked where they are shown, which is not where
+	// the line was cut off
+	last := Width(shown)
+	if last < Width(line) {
+		last -= Width(tg.ellipsis)
+	}
+
+	start := Width(tg.label) + 2
+	for i, option := range []string{first, second} {
+		tg.columns[i] = [2]int{start, min(start+Width(option)-1, last)}
+		start += Width(option) + theme.Gap
+	}
+}
+
+// locate queries on which row the toggle is shown, which is only needed
+// when using the mouse.
+func (tg *Toggle[T]) locate(sn *session) {
+
+	tg.row = 0
+
+	if sn.opts.mouse {
+		sn.queryCursor()
+	}
+}
+
+// optionAt returns the option shown at the given screen column and row.
+func (tg *Toggle[T]) optionAt(col, row int) (int, bool) {
+
+	if tg.row == 0 || row != tg.row {
+		return 0, false
+	}
+
+	for i, c := range tg.columns {
+		if col >= c[0] && col <= c[1] {
+			return i, true
+		}
+	}
+
+	return 0, false
 }
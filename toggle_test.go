@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golistic/console"
+	"github.com/golistic/console/consoletest"
+)
+
+func newYesNoToggle(t *testing.T, term *consoletest.Terminal) *console.Toggle[bool] {
+
+	t.Helper()
+
+	tg, err := console.NewToggle("Continue?", []string{"Yes", "No"}, []bool{true, false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg.SetTerminal(term)
+
+	return tg
+}
+
+func TestToggle_golden(t *testing.T) {
+
+	// the background decides between the dark and light variant of color01
+	t.Setenv("COLORFGBG", "")
+
+	cases := []struct {
+		name  string
+		theme console.Theme
+		keys  []string
+	}{
+		{name: "toggle_initial", theme: console.ThemeAscii},
+		{name: "toggle_right", theme: console.ThemeAscii, keys: []string{consoletest.Right}},
+		{name: "toggle_color01", theme: console.ThemeColor01, keys: []string{consoletest.Tab}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(40, 5)
+			term.Type(c.keys...)
+			term.Do(func() {
+				consoletest.Golden(t, c.name, term.Screen().StyledString())
+			})
+			term.Type(consoletest.Escape)
+
+			tg := newYesNoToggle(t, term)
+			if err := tg.RenderWithTheme(c.theme); !errors.Is(err, console.ErrAborted) {
+				t.Fatalf("got error %v; want ErrAborted", err)
+			}
+		})
+	}
+}
+
+func TestToggle_keys(t *testing.T) {
+
+	cases := []struct {
+		name string
+		keys []string
+		want bool
+		err  error
+	}{
+		{name: "enter", keys: []string{consoletest.Enter}, want: true},
+		{name: "right", keys: []string{consoletest.Right, consoletest.Enter}, want: false},
+		{name: "right and left", keys: []string{consoletest.Right, consoletest.Left, consoletest.Enter}, want: true},
+		{name: "tab", keys: []string{consoletest.Tab, consoletest.Enter}, want: false},
+		{name: "tab twice", keys: []string{consoletest.Tab, consoletest.Tab, consoletest.Enter}, want: true},
+		{name: "end", keys: []string{consoletest.End, consoletest.Enter}, want: false},
+		{name: "escape", keys: []string{consoletest.Escape}, err: console.ErrAborted},
+		{name: "interrupt", keys: []string{consoletest.CtrlC}, err: console.ErrAborted},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(40, 5)
+			term.Type(c.keys...)
+
+			tg := newYesNoToggle(t, term)
+			err := tg.RenderWithTheme(console.ThemeAscii)
+			if c.err != nil {
+				if !errors.Is(err, c.err) {
+					t.Fatalf("got error %v; want %v", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := tg.Selected(); got != c.want {
+				t.Errorf("got %v; want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestToggle_lines(t *testing.T) {
+
+	cases := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "default", input: "\n", want: true},
+		{name: "no", input: "n\n", want: false},
+		{name: "option", input: "NO\n", want: false},
+		{name: "retry", input: "maybe\nyes\n", want: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(40, 5)
+			term.SetIsTerminal(false)
+			term.TypeText(c.input)
+
+			tg := newYesNoToggle(t, term)
+			if err := tg.RenderWithTheme(console.ThemeAscii); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := tg.Selected(); got != c.want {
+				t.Errorf("got %v; want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestToggle_background(t *testing.T) {
+
+	t.Setenv("COLORFGBG", "")
+
+	styled := func(background *[3]uint8) string {
+		term := consoletest.NewTerminal(40, 5)
+		if background != nil {
+			term.Screen().SetBackgroundColor(background[0], background[1], background[2])
+		}
+
+		var got string
+		term.Do(func() { got = term.Screen().StyledString() })
+		term.Type(consoletest.Enter)
+
+		tg := newYesNoToggle(t, term)
+		if err := tg.RenderWithTheme(console.ThemeColor01); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	dark := styled(&[3]uint8{0, 0, 0})
+	light := styled(&[3]uint8{255, 255, 255})
+	unknown := styled(nil)
+
+	if dark == light {
+		t.Errorf("expected the light variant on a light background; both are %q", dark)
+	}
+	if unknown != dark {
+		t.Errorf("got %q when the background is not reported; want the dark variant %q", unknown, dark)
+	}
+}
+
+func TestToggle_narrow(t *testing.T) {
+
+	term := consoletest.NewTerminal(12, 5)
+	term.Type(consoletest.Right)
+	term.Do(func() {
+		if got, want := term.Screen().String(), "Continue? …"; got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+	term.Type(consoletest.Escape)
+
+	tg := newYesNoToggle(t, term)
+	if err := tg.RenderWithTheme(console.ThemeAscii); !errors.Is(err, console.ErrAborted) {
+		t.Fatalf("got error %v; want ErrAborted", err)
+	}
+
+	if got := term.Screen().String(); got != "" {
+		t.Errorf("expected screen to be cleared; got:\n%s", got)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+// widget holds what all interactive widgets have in common.
+type widget struct {
+	terminal  Terminal
+	fallback  Fallback
+	mouse     bool
+	altScreen bool
+	// input was read by a Form before the widget was rendered
+	input []byte
+}
+
+// SetTerminal sets the Terminal used for reading input and writing output.
+// When not set, the standard input and output of the process is used.
+func (w *widget) SetTerminal(t Terminal) {
+
+	w.terminal = t
+}
+
+func (w *widget) getTerminal() Terminal {
+
+	if w.terminal == nil {
+		return StdTerminal()
+	}
+
+	return w.terminal
+}
+
+// SetFallback sets what is done when the terminal is not interactive. By
+// default, the widget falls back to prompting for a line of input.
+func (w *widget) SetFallback(f Fallback) {
+
+	w.fallback = f
+}
+
+// SetMouse sets whether the mouse can be used while the widget is rendered.
+// Mouse reporting is disabled again when rendering is done.
+func (w *widget) SetMouse(enabled bool) {
+
+	w.mouse = enabled
+}
+
+// SetAltScreen sets whether the widget is rendered full-screen, using the
+// alternate screen buffer of the terminal. What was shown before is restored
+// when rendering is done.
+func (w *widget) SetAltScreen(enabled bool) {
+
+	w.altScreen = enabled
+}
+
+func (w *widget) sessionOptions() sessionOptions {
+
+	return sessionOptions{
+		mouse:     w.mouse,
+		altScreen: w.altScreen,
+		input:     w.input,
+	}
+}
+
+// interactive returns whether the widget can be rendered interactively
+// on t, or ErrNotTerminal when it should not fall back to line input.
+func (w *widget) interactive(t Terminal) (bool, error) {
+
+	if t.IsTerminal() {
+		return true, nil
+	}
+
+	if w.fallback == FallbackError {
+		return false, ErrNotTerminal
+	}
+
+	return false, nil
+}
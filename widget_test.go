@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golistic/console"
+	"github.com/golistic/console/consoletest"
+)
+
+func TestSelection_suspend(t *testing.T) {
+
+	cases := []struct {
+		name    string
+		request func(term *consoletest.Terminal)
+	}{
+		{name: "ctrl+z", request: func(term *consoletest.Terminal) { term.Type(consoletest.CtrlZ) }},
+		{name: "SIGTSTP", request: func(term *consoletest.Terminal) { term.RequestSuspend() }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := consoletest.NewTerminal(30, 10)
+
+			var suspended int
+			term.OnSuspend(func() {
+				suspended++
+				if got := term.Screen().String(); got != "" {
+					t.Errorf("expected options cleared while suspended; got:\n%s", got)
+				}
+				if term.Raw() || !term.Screen().CursorVisible() {
+					t.Error("expected terminal restored while suspended")
+				}
+			})
+
+			term.Type(consoletest.Down, consoletest.Down)
+			c.request(term)
+			term.Do(func() {
+				consoletest.Golden(t, "selection_down", term.Screen().StyledString())
+				if !term.Raw() || term.Screen().CursorVisible() {
+					t.Error("expected terminal set up again after continuing")
+				}
+			})
+			term.Type(consoletest.Enter)
+
+			s := newFruitSelection(t, term)
+			if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+				t.Fatal(err)
+			}
+
+			if suspended != 1 {
+				t.Errorf("got suspended %d times; want once", suspended)
+			}
+			if got := s.Selected(); got != 3 {
+				t.Errorf("got %d; want 3", got)
+			}
+		})
+	}
+}
+
+func TestSelection_resize(t *testing.T) {
+
+	term := consoletest.NewTerminal(30, 10)
+	term.Type(consoletest.End)
+	term.Resize(30, 5)
+	term.Do(func() {
+		// two options fit below the height minus three lines
+		if got, want := term.Screen().String(), "    elderberry\n > fig"; got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+	term.Type(consoletest.Up, consoletest.Up)
+	term.Do(func() {
+		if got, want := term.Screen().String(), " > date\n    elderberry"; got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+	term.Resize(30, 10)
+	term.Do(func() {
+		consoletest.Golden(t, "selection_resized", term.Screen().String())
+	})
+	term.Type(consoletest.Enter)
+
+	s := newFruitSelection(t, term)
+	if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.Selected(); got != 4 {
+		t.Errorf("got %d; want 4", got)
+	}
+}
+
+func TestSelection_altScreen(t *testing.T) {
+
+	term := consoletest.NewTerminal(30, 10)
+	fmt.Fprint(term, "$ demo\r\n")
+
+	term.Type(consoletest.Down)
+	term.Do(func() {
+		if !term.Screen().Mode(1049) {
+			t.Error("expected the alternate screen while rendering")
+		}
+		if got, want := term.Screen().Line(1), " > banana"; got != want {
+			t.Errorf("got %q; want %q on the alternate screen", got, want)
+		}
+	})
+	term.Type(consoletest.Enter)
+
+	s := newFruitSelection(t, term)
+	s.SetAltScreen(true)
+	if err := s.RenderWithTheme(console.ThemeAscii); err != nil {
+		t.Fatal(err)
+	}
+
+	if term.Screen().Mode(1049) {
+		t.Error("expected the alternate screen to be left")
+	}
+	if got, want := term.Screen().String(), "$ demo"; got != want {
+		t.Errorf("got:\n%s\nwant what was shown before:\n%s", got, want)
+	}
+	if got := s.Selected(); got != 2 {
+		t.Errorf("got %d; want 2", got)
+	}
+}
+
+func TestForm_altScreen(t *testing.T) {
+
+	term := consoletest.NewTerminal(40, 10)
+	fmt.Fprint(term, "$ demo\r\n")
+
+	term.Do(func() {
+		if !term.Screen().Mode(1049) {
+			t.Error("expected the alternate screen while the form is shown")
+		}
+	})
+	term.Type(consoletest.Enter)
+
+	form := console.NewForm().SetTerminal(term).SetAltScreen(true)
+	form.AddElements(console.NewFormToggleBool("ok", "OK?", nil, true))
+	if err := form.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if term.Screen().Mode(1049) {
+		t.Error("expected the alternate screen to be left")
+	}
+	if got, want := term.Screen().String(), "$ demo"; got != want {
+		t.Errorf("got:\n%s\nwant what was shown before:\n%s", got, want)
+	}
+}
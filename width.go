@@ -0,0 +1,290 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// ansiSequence matches the CSI and OSC escape sequences in text.
+var ansiSequence = regexp.MustCompile(`\x1b(\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)?)`)
+
+const zeroWidthJoiner = '\u200d'
+
+// StripANSI removes the escape sequences, such as colors, from s.
+func StripANSI(s string) string {
+
+	return ansiSequence.ReplaceAllString(s, "")
+}
+
+// runeWidth returns the number of columns r takes in the terminal: 0 for
+// combining marks and format characters, 2 for wide East Asian characters
+// and emoji, and 1 otherwise.
+func runeWidth(r rune) int {
+
+	switch {
+	case r < 0x20 || r == 0x7f:
+		return 0
+	case r == zeroWidthJoiner || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case r >= 0x1f1e6 && r <= 0x1f1ff, r >= 0x1f300 && r <= 0x1faff:
+		return 2
+	}
+
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+
+	return 1
+}
+
+func isRegionalIndicator(r rune) bool {
+
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// textMeasure keeps track of the width of text read rune by rune, so that
+// characters joined using a zero width joiner, and pairs of regional
+// indicators forming a flag, are counted as one character.
+type textMeasure struct {
+	prev     rune
+	flagOpen bool
+}
+
+// add returns the number of columns r adds to the text measured so far.
+func (m *textMeasure) add(r rune) int {
+
+	prev := m.prev
+	m.prev = r
+
+	switch {
+	case prev == zeroWidthJoiner:
+		return 0
+	case isRegionalIndicator(r):
+		m.flagOpen = !m.flagOpen
+		if m.flagOpen {
+			return 2
+		}
+		return 0
+	}
+
+	m.flagOpen = false
+
+	return runeWidth(r)
+}
+
+// Width returns the number of columns s takes in the terminal. Escape sequences
+// do not take any space, wide East Asian characters and emoji take two columns,
+// and combining characters none.
+func Width(s string) int {
+
+	var m textMeasure
+	var n int
+
+	for _, r := range StripANSI(s) {
+		n += m.add(r)
+	}
+
+	return n
+}
+
+// truncateText cuts s so that it takes at most width columns. When a style is
+// still in effect where s is cut, it is reset.
+func truncateText(s string, width int) string {
+
+	if Width(s) <= width {
+		return s
+	}
+
+	head, _ := cutText(s, width)
+	if sgrActive("", head) != "" {
+		head += sgrReset
+	}
+
+	return head
+}
+
+// cutText splits s after width columns. The first part always contains at
+// least one character, even when it is wider than width.
+func cutText(s string, width int) (string, string) {
+
+	var m textMeasure
+	var n int
+
+	i := 0
+	for i < len(s) {
+		if s[i] == 0x1b {
+			if loc := ansiSequence.FindStringIndex(s[i:]); loc != nil && loc[0] == 0 {
+				i += loc[1]
+				continue
+			}
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := m.add(r)
+		if n+w > width && n > 0 {
+			break
+		}
+		n += w
+		i += size
+	}
+
+	return s[:i], s[i:]
+}
+
+// Truncate cuts s so that it takes at most width columns, ending it with
+// ellipsis when it was cut. Styles in effect where s is cut are reset.
+func Truncate(s string, width int, ellipsis string) string {
+
+	if Width(s) <= width {
+		return s
+	}
+
+	n := Width(ellipsis)
+	if width <= n {
+		return truncateText(ellipsis, width)
+	}
+
+	return truncateText(s, width-n) + ellipsis
+}
+
+// TruncateMiddle cuts s so that it takes at most width columns, replacing
+// the middle of s with ellipsis so that both the start and the end stay
+// visible, which suits paths. Styles in effect where s is cut are reset
+// before the ellipsis, and set again after it.
+func TruncateMiddle(s string, width int, ellipsis string) string {
+
+	total := Width(s)
+	if total <= width {
+		return s
+	}
+
+	n := Width(ellipsis)
+	if width <= n {
+		return truncateText(ellipsis, width)
+	}
+
+	tail := (width - n) / 2
+	head := width - n - tail
+
+	return truncateText(s, head) + ellipsis + lastColumns(s, tail, total)
+}
+
+// lastColumns returns the end of s, which takes total columns, taking at most
+// width columns. Styles in effect where the end starts are set again.
+func lastColumns(s string, width, total int) string {
+
+	if width <= 0 {
+		return ""
+	}
+
+	// wide characters can make the end take more columns than asked
+	var front, back string
+	for skip := total - width; ; skip++ {
+		front, back = cutText(s, skip)
+		if Width(back) <= width {
+			break
+		}
+	}
+
+	active := sgrActive("", front)
+	if sgrActive(active, back) != "" {
+		back += sgrReset
+	}
+
+	return active + back
+}
+
+// Wrap breaks s into lines which take at most width columns. Lines are broken
+// between words when possible. Styles continuing on the next line are reset at
+// the end of the line and set again at the start of the next.
+func Wrap(s string, width int) []string {
+
+	width = max(1, width)
+
+	var lines []string
+	var line string
+
+	for _, word := range strings.Fields(s) {
+		switch {
+		case line == "":
+			line = word
+		case Width(line)+1+Width(word) <= width:
+			line += " " + word
+			continue
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+
+		for Width(line) > width {
+			var head string
+			head, line = cutText(line, width)
+			lines = append(lines, head)
+		}
+	}
+	lines = append(lines, line)
+
+	var active string
+	for i, line := range lines {
+		next := sgrActive(active, line)
+		lines[i] = active + line
+		if next != "" {
+			lines[i] += sgrReset
+		}
+		active = next
+	}
+
+	return lines
+}
+
+// sgrActive returns the SGR escape sequences in effect after s, when those in
+// active were in effect before it.
+func sgrActive(active, s string) string {
+
+	for _, seq := range ansiSequence.FindAllString(s, -1) {
+		switch {
+		case seq == sgrReset || seq == "\033[m":
+			active = ""
+		case strings.HasSuffix(seq, "m"):
+			active += seq
+		}
+	}
+
+	return active
+}
+
+// blankText replaces the characters of s with spaces, keeping its escape
+// sequences.
+func blankText(s string) string {
+
+	var b strings.Builder
+
+	last := 0
+	for _, loc := range ansiSequence.FindAllStringIndex(s, -1) {
+		b.WriteString(strings.Repeat(" ", Width(s[last:loc[0]])))
+		b.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(strings.Repeat(" ", Width(s[last:])))
+
+	return b.String()
+}
+
+// Pad adds spaces to the end of s so that it takes at least width columns.
+func Pad(s string, width int) string {
+
+	if n := Width(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+
+	return s
+}
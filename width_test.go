@@ -0,0 +1,190 @@
+/*
+ * Copyright (c) 2025, Geert JM Vanderkelen
+ */
+
+package console
+
+import (
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+
+	cases := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{name: "empty", s: "", want: 0},
+		{name: "ascii", s: "hello", want: 5},
+		{name: "styled", s: "\033[1;32mhello\033[0m", want: 5},
+		{name: "hyperlink", s: "\033]8;;https://example.com\033\\link\033]8;;\033\\", want: 4},
+		{name: "accented", s: "café", want: 4},
+		{name: "combining accent", s: "cafe\u0301", want: 4},
+		{name: "wide", s: "日本語", want: 6},
+		{name: "fullwidth", s: "ＡＢ", want: 4},
+		{name: "emoji", s: "🎉", want: 2},
+		{name: "zero width joiner sequence", s: "👩‍💻", want: 2},
+		{name: "flag", s: "🇳🇱", want: 2},
+		{name: "two flags", s: "🇳🇱🇧🇪", want: 4},
+		{name: "control characters", s: "a\tb", want: 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := Width(c.s); got != c.want {
+				t.Errorf("got %d; want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		s        string
+		width    int
+		ellipsis string
+		want     string
+	}{
+		{name: "fits", s: "hello", width: 5, ellipsis: "…", want: "hello"},
+		{name: "cut", s: "hello world", width: 8, ellipsis: "…", want: "hello w…"},
+		{name: "ascii ellipsis", s: "hello world", width: 8, ellipsis: "...", want: "hello..."},
+		{name: "width of ellipsis", s: "hello", width: 3, ellipsis: "...", want: "..."},
+		{name: "narrower than ellipsis", s: "hello", width: 2, ellipsis: "...", want: ".."},
+		{name: "wide characters", s: "日本語です", width: 5, ellipsis: "…", want: "日本…"},
+		{name: "wide character does not fit", s: "日本語です", width: 6, ellipsis: "…", want: "日本…"},
+		{name: "style reset", s: "\033[1mhello world\033[0m", width: 6, ellipsis: "…",
+			want: "\033[1mhello" + sgrReset + "…"},
+		{name: "style ended before cut", s: "\033[1mhi\033[0m there", width: 6, ellipsis: "…",
+			want: "\033[1mhi\033[0m th…"},
+		{name: "combining accent kept", s: "cafe\u0301s and more", width: 6, ellipsis: "…", want: "cafe\u0301s…"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := Truncate(c.s, c.width, c.ellipsis)
+			if got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+			if Width(got) > c.width {
+				t.Errorf("got width %d; want at most %d", Width(got), c.width)
+			}
+		})
+	}
+}
+
+func TestPad(t *testing.T) {
+
+	cases := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{name: "padded", s: "ab", width: 4, want: "ab  "},
+		{name: "wide", s: "日", width: 4, want: "日  "},
+		{name: "styled", s: "\033[1mab\033[0m", width: 3, want: "\033[1mab\033[0m "},
+		{name: "wider than width", s: "abcdef", width: 4, want: "abcdef"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := Pad(c.s, c.width); got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+
+	cases := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "plain", s: "hello", want: "hello"},
+		{name: "colors", s: "\033[1;32mhello\033[0m world", want: "hello world"},
+		{name: "cursor movement", s: "a\033[2Kb\033[1A", want: "ab"},
+		{name: "hyperlink terminated by ST", s: "\033]8;;https://example.com\033\\link\033]8;;\033\\", want: "link"},
+		{name: "hyperlink terminated by BEL", s: "\033]8;;x\alink\033]8;;\a", want: "link"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := StripANSI(c.s); got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		s        string
+		width    int
+		ellipsis string
+		want     string
+	}{
+		{name: "fits", s: "/usr/local/bin", width: 14, ellipsis: "…", want: "/usr/local/bin"},
+		{name: "path", s: "/usr/local/share/doc/console", width: 13, ellipsis: "…", want: "/usr/l…onsole"},
+		{name: "ascii ellipsis", s: "abcdefghij", width: 7, ellipsis: "...", want: "ab...ij"},
+		{name: "style kept", s: "\033[1mabcdefghij\033[0m", width: 5, ellipsis: "…", want: "\033[1mab" + sgrReset + "…\033[1mij\033[0m"},
+		{name: "style set again", s: "\033[31mabcdefghij", width: 5, ellipsis: "…", want: "\033[31mab" + sgrReset + "…\033[31mij" + sgrReset},
+		{name: "style in cut middle", s: "abc\033[31mdef\033[0mghij", width: 5, ellipsis: "…", want: "ab…ij"},
+		{name: "style in tail", s: "abcdefgh\033[32mij\033[0m", width: 5, ellipsis: "…", want: "ab…\033[32mij\033[0m"},
+		{name: "wide characters", s: "日本語のテキスト", width: 7, ellipsis: "…", want: "日…ト"},
+		{name: "narrower than ellipsis", s: "abcdef", width: 2, ellipsis: "...", want: ".."},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := TruncateMiddle(c.s, c.width, c.ellipsis)
+			if got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+			if Width(got) > c.width {
+				t.Errorf("got width %d; want at most %d", Width(got), c.width)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+
+	cases := []struct {
+		name  string
+		s     string
+		width int
+		want  []string
+	}{
+		{name: "fits", s: "hello world", width: 20, want: []string{"hello world"}},
+		{name: "between words", s: "the quick brown fox", width: 10, want: []string{"the quick", "brown fox"}},
+		{name: "spaces collapsed", s: "  a   b  ", width: 10, want: []string{"a b"}},
+		{name: "long word cut", s: "abcdefghij", width: 4, want: []string{"abcd", "efgh", "ij"}},
+		{name: "long word after short", s: "a bcdefgh", width: 4, want: []string{"a", "bcde", "fgh"}},
+		{name: "wide characters", s: "日本語です", width: 4, want: []string{"日本", "語で", "す"}},
+		{name: "zero width", s: "ab", width: 0, want: []string{"a", "b"}},
+		{name: "empty", s: "", width: 4, want: []string{""}},
+		{name: "style continues", s: "\033[1mbold text here\033[0m", width: 9,
+			want: []string{"\033[1mbold text" + sgrReset, "\033[1mhere\033[0m"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := Wrap(c.s, c.width)
+			if len(got) != len(c.want) {
+				t.Fatalf("got %q; want %q", got, c.want)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Errorf("line %d: got %q; want %q", i, got[i], c.want[i])
+				}
+			}
+		})
+	}
+}